@@ -48,15 +48,21 @@ func (p *Program) String() string {
 	return out.String()
 }
 
-// LetStatement represents a let statement.
+// LetStatement represents a let statement, or a const statement whose bindings cannot be
+// redeclared.
 type LetStatement struct {
-	Token token.Token // the token.LET token
-	Name  *Ident
+	Token token.Token // the token.LET or token.CONST token
+	Name  Expression  // an *Ident, or an *ArrayPattern or a *HashPattern to destructure Value
 	Value Expression
 }
 
 func (ls *LetStatement) statementNode() {}
 
+// IsConst reports whether the statement is a const statement.
+func (ls *LetStatement) IsConst() bool {
+	return ls.Token.Type == token.CONST
+}
+
 // TokenLiteral returns a token literal of let statement.
 func (ls *LetStatement) TokenLiteral() string {
 	return ls.Token.Literal
@@ -122,6 +128,40 @@ func (rs *ReturnStatement) String() string {
 	return out.String()
 }
 
+// ThrowStatement represents a throw statement.
+type ThrowStatement struct {
+	Token token.Token // the token.THROW token
+	Value Expression
+}
+
+func (ts *ThrowStatement) statementNode() {}
+
+// TokenLiteral returns a token literal of throw statement.
+func (ts *ThrowStatement) TokenLiteral() string {
+	if ts == nil {
+		return ""
+	}
+	return ts.Token.Literal
+}
+
+func (ts *ThrowStatement) String() string {
+	if ts == nil {
+		return ""
+	}
+
+	var out bytes.Buffer
+
+	out.WriteString(ts.TokenLiteral() + " ")
+
+	if ts.Value != nil {
+		out.WriteString(ts.Value.String())
+	}
+
+	out.WriteString(";")
+
+	return out.String()
+}
+
 // ExpressionStatement represents an expression statement.
 type ExpressionStatement struct {
 	Token      token.Token // the first token of the expression
@@ -277,6 +317,87 @@ func (ie *IfExpression) String() string {
 	return out.String()
 }
 
+// ConditionalExpression represents a conditional expression such as `x > 0 ? x : -x`.
+type ConditionalExpression struct {
+	Token       token.Token // the '?' token
+	Condition   Expression
+	Consequence Expression
+	Alternative Expression
+}
+
+func (*ConditionalExpression) expressionNode() {}
+
+// TokenLiteral returns a token literal of conditional expression.
+func (ce *ConditionalExpression) TokenLiteral() string {
+	if ce == nil {
+		return ""
+	}
+	return ce.Token.Literal
+}
+
+func (ce *ConditionalExpression) String() string {
+	if ce == nil {
+		return ""
+	}
+
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(ce.Condition.String())
+	out.WriteString(" ? ")
+	out.WriteString(ce.Consequence.String())
+	out.WriteString(" : ")
+	out.WriteString(ce.Alternative.String())
+	out.WriteString(")")
+
+	return out.String()
+}
+
+// TryExpression represents a try expression, which has a catch block, a finally block or both.
+type TryExpression struct {
+	Token      token.Token // the token.TRY token
+	Block      *BlockStatement
+	CatchParam *Ident          // nil if the catch block takes no parameter
+	Catch      *BlockStatement // nil if there is no catch block
+	Finally    *BlockStatement // nil if there is no finally block
+}
+
+func (te *TryExpression) expressionNode() {}
+
+// TokenLiteral returns a token literal of try expression.
+func (te *TryExpression) TokenLiteral() string {
+	if te == nil {
+		return ""
+	}
+	return te.Token.Literal
+}
+
+func (te *TryExpression) String() string {
+	if te == nil {
+		return ""
+	}
+
+	var out bytes.Buffer
+
+	out.WriteString("try ")
+	out.WriteString(te.Block.String())
+
+	if te.Catch != nil {
+		out.WriteString(" catch ")
+		if te.CatchParam != nil {
+			out.WriteString("(" + te.CatchParam.String() + ") ")
+		}
+		out.WriteString(te.Catch.String())
+	}
+
+	if te.Finally != nil {
+		out.WriteString(" finally ")
+		out.WriteString(te.Finally.String())
+	}
+
+	return out.String()
+}
+
 // BlockStatement represents a block statement.
 type BlockStatement struct {
 	Token      token.Token // the '{' token
@@ -303,7 +424,8 @@ func (bs *BlockStatement) String() string {
 // FunctionLiteral represents a fuction literal.
 type FunctionLiteral struct {
 	Token      token.Token
-	Parameters []*Ident
+	Parameters []Expression // each is an *Ident, or an *ArrayPattern or a *HashPattern
+	Rest       *Ident       // nil if the function takes no rest parameter
 	Body       *BlockStatement
 }
 
@@ -317,10 +439,13 @@ func (fl *FunctionLiteral) TokenLiteral() string {
 func (fl *FunctionLiteral) String() string {
 	var out bytes.Buffer
 
-	params := make([]string, 0, len(fl.Parameters))
+	params := make([]string, 0, len(fl.Parameters)+1)
 	for _, p := range fl.Parameters {
 		params = append(params, p.String())
 	}
+	if fl.Rest != nil {
+		params = append(params, "..."+fl.Rest.String())
+	}
 
 	out.WriteString(fl.TokenLiteral())
 	out.WriteString("(")
@@ -449,10 +574,91 @@ func (ie *IndexExpression) String() string {
 	return out.String()
 }
 
+// SliceExpression represents an expression in slice operator, e.g. `array[low:high]`.
+type SliceExpression struct {
+	Token token.Token // the '[' token
+	Left  Expression
+	Low   Expression // nil if omitted
+	High  Expression // nil if omitted
+}
+
+func (*SliceExpression) expressionNode() {}
+
+// TokenLiteral returns a token literal of slice expression.
+func (se *SliceExpression) TokenLiteral() string {
+	if se == nil {
+		return ""
+	}
+	return se.Token.Literal
+}
+
+func (se *SliceExpression) String() string {
+	if se == nil {
+		return ""
+	}
+
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(se.Left.String())
+	out.WriteString("[")
+	if se.Low != nil {
+		out.WriteString(se.Low.String())
+	}
+	out.WriteString(":")
+	if se.High != nil {
+		out.WriteString(se.High.String())
+	}
+	out.WriteString("])")
+
+	return out.String()
+}
+
+// MemberExpression represents an expression in member access operator, e.g. `hash.key`.
+type MemberExpression struct {
+	Token  token.Token // the '.' token
+	Left   Expression
+	Member *Ident
+}
+
+func (*MemberExpression) expressionNode() {}
+
+// TokenLiteral returns a token literal of member access operator.
+func (me *MemberExpression) TokenLiteral() string {
+	if me == nil {
+		return ""
+	}
+	return me.Token.Literal
+}
+
+func (me *MemberExpression) String() string {
+	if me == nil {
+		return ""
+	}
+
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(me.Left.String())
+	out.WriteString(".")
+	out.WriteString(me.Member.String())
+	out.WriteString(")")
+
+	return out.String()
+}
+
+// HashPair represents a key-value pair in a hash literal.
+// In a hash literal, a pair may instead spread another hash, in which case Key is a
+// *SpreadExpression and Value is nil.
+type HashPair struct {
+	Key   Expression
+	Value Expression
+}
+
 // HashLiteral represents a hash literal.
 type HashLiteral struct {
 	Token token.Token // the '{' token
-	Pairs map[Expression]Expression
+	Pairs []HashPair  // in source order
 }
 
 func (*HashLiteral) expressionNode() {}
@@ -470,9 +676,154 @@ func (hl *HashLiteral) String() string {
 		return ""
 	}
 
-	pairs := make([]string, len(hl.Pairs))
-	for key, value := range hl.Pairs {
-		pairs = append(pairs, key.String()+": "+value.String())
+	pairs := make([]string, 0, len(hl.Pairs))
+	for _, pair := range hl.Pairs {
+		if pair.Value == nil {
+			pairs = append(pairs, pair.Key.String())
+			continue
+		}
+		pairs = append(pairs, pair.Key.String()+": "+pair.Value.String())
+	}
+
+	var out bytes.Buffer
+	out.WriteString("{")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString("}")
+	return out.String()
+}
+
+// SpreadExpression represents an array spread into the arguments of a call or the elements of an
+// array literal, or a hash spread into a hash literal, e.g. `...xs`.
+type SpreadExpression struct {
+	Token token.Token // the token.ELLIPSIS token
+	Value Expression
+}
+
+func (*SpreadExpression) expressionNode() {}
+
+// TokenLiteral returns a token literal of spread expression.
+func (se *SpreadExpression) TokenLiteral() string {
+	if se == nil {
+		return ""
+	}
+	return se.Token.Literal
+}
+
+func (se *SpreadExpression) String() string {
+	if se == nil {
+		return ""
+	}
+	return "..." + se.Value.String()
+}
+
+// MatchArm represents an arm of a match expression.
+type MatchArm struct {
+	Pattern Expression
+	Guard   Expression // nil if the arm has no guard
+	Body    Expression
+}
+
+// MatchExpression represents a match expression.
+type MatchExpression struct {
+	Token token.Token // the token.MATCH token
+	Value Expression
+	Arms  []MatchArm // in source order
+}
+
+func (*MatchExpression) expressionNode() {}
+
+// TokenLiteral returns a token literal of match expression.
+func (me *MatchExpression) TokenLiteral() string {
+	if me == nil {
+		return ""
+	}
+	return me.Token.Literal
+}
+
+func (me *MatchExpression) String() string {
+	if me == nil {
+		return ""
+	}
+
+	arms := make([]string, 0, len(me.Arms))
+	for _, arm := range me.Arms {
+		s := arm.Pattern.String()
+		if arm.Guard != nil {
+			s += " if " + arm.Guard.String()
+		}
+		arms = append(arms, s+" => "+arm.Body.String())
+	}
+
+	var out bytes.Buffer
+	out.WriteString("match (")
+	out.WriteString(me.Value.String())
+	out.WriteString(") {")
+	out.WriteString(strings.Join(arms, ", "))
+	out.WriteString("}")
+	return out.String()
+}
+
+// ArrayPattern represents an array pattern such as `[head, ..tail]`.
+type ArrayPattern struct {
+	Token    token.Token // the '[' token
+	Elements []Expression
+	Rest     *Ident // nil if the pattern has no rest element
+}
+
+func (*ArrayPattern) expressionNode() {}
+
+// TokenLiteral returns a token literal of array pattern.
+func (ap *ArrayPattern) TokenLiteral() string {
+	if ap == nil {
+		return ""
+	}
+	return ap.Token.Literal
+}
+
+func (ap *ArrayPattern) String() string {
+	if ap == nil {
+		return ""
+	}
+
+	elements := make([]string, 0, len(ap.Elements)+1)
+	for _, elem := range ap.Elements {
+		elements = append(elements, elem.String())
+	}
+	if ap.Rest != nil {
+		elements = append(elements, ".."+ap.Rest.String())
+	}
+
+	var out bytes.Buffer
+	out.WriteString("[")
+	out.WriteString(strings.Join(elements, ", "))
+	out.WriteString("]")
+	return out.String()
+}
+
+// HashPattern represents a hash pattern such as `{"name": name}`.
+type HashPattern struct {
+	Token token.Token // the '{' token
+	Pairs []HashPair  // keys are literals and values are patterns, in source order
+}
+
+func (*HashPattern) expressionNode() {}
+
+// TokenLiteral returns a token literal of hash pattern.
+func (hp *HashPattern) TokenLiteral() string {
+	if hp == nil {
+		return ""
+	}
+	return hp.Token.Literal
+}
+
+func (hp *HashPattern) String() string {
+	if hp == nil {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(hp.Pairs))
+	for _, pair := range hp.Pairs {
+		pairs = append(pairs, pair.Key.String()+": "+pair.Value.String())
 	}
 
 	var out bytes.Buffer
@@ -0,0 +1,355 @@
+package ast
+
+import "fmt"
+
+// Clone returns a deep copy of `node`. The copy shares no mutable state with the original, so it
+// can be modified, e.g. by Modify, without affecting `node`. Clone returns nil if `node` is nil.
+func Clone(node Node) Node {
+	switch node := node.(type) {
+	case nil:
+		return nil
+
+	// Statements
+
+	case *Program:
+		if node == nil {
+			return node
+		}
+		return &Program{Statements: cloneStatements(node.Statements)}
+
+	case *LetStatement:
+		if node == nil {
+			return node
+		}
+		return &LetStatement{
+			Token: node.Token,
+			Name:  cloneExpression(node.Name),
+			Value: cloneExpression(node.Value),
+		}
+
+	case *ReturnStatement:
+		if node == nil {
+			return node
+		}
+		return &ReturnStatement{
+			Token:       node.Token,
+			ReturnValue: cloneExpression(node.ReturnValue),
+		}
+
+	case *ThrowStatement:
+		if node == nil {
+			return node
+		}
+		return &ThrowStatement{
+			Token: node.Token,
+			Value: cloneExpression(node.Value),
+		}
+
+	case *ExpressionStatement:
+		if node == nil {
+			return node
+		}
+		return &ExpressionStatement{
+			Token:      node.Token,
+			Expression: cloneExpression(node.Expression),
+		}
+
+	case *BlockStatement:
+		return cloneBlockStatement(node)
+
+	// Expressions
+
+	case *Ident:
+		return cloneIdent(node)
+
+	case *IntegerLiteral:
+		if node == nil {
+			return node
+		}
+		lit := *node
+		return &lit
+
+	case *FloatLiteral:
+		if node == nil {
+			return node
+		}
+		lit := *node
+		return &lit
+
+	case *Boolean:
+		if node == nil {
+			return node
+		}
+		lit := *node
+		return &lit
+
+	case *StringLiteral:
+		if node == nil {
+			return node
+		}
+		lit := *node
+		return &lit
+
+	case *PrefixExpression:
+		if node == nil {
+			return node
+		}
+		return &PrefixExpression{
+			Token:    node.Token,
+			Operator: node.Operator,
+			Right:    cloneExpression(node.Right),
+		}
+
+	case *InfixExpression:
+		if node == nil {
+			return node
+		}
+		return &InfixExpression{
+			Token:    node.Token,
+			Left:     cloneExpression(node.Left),
+			Operator: node.Operator,
+			Right:    cloneExpression(node.Right),
+		}
+
+	case *IfExpression:
+		if node == nil {
+			return node
+		}
+		return &IfExpression{
+			Token:       node.Token,
+			Condition:   cloneExpression(node.Condition),
+			Consequence: cloneBlockStatement(node.Consequence),
+			Alternative: cloneBlockStatement(node.Alternative),
+		}
+
+	case *ConditionalExpression:
+		if node == nil {
+			return node
+		}
+		return &ConditionalExpression{
+			Token:       node.Token,
+			Condition:   cloneExpression(node.Condition),
+			Consequence: cloneExpression(node.Consequence),
+			Alternative: cloneExpression(node.Alternative),
+		}
+
+	case *TryExpression:
+		if node == nil {
+			return node
+		}
+		return &TryExpression{
+			Token:      node.Token,
+			Block:      cloneBlockStatement(node.Block),
+			CatchParam: cloneIdent(node.CatchParam),
+			Catch:      cloneBlockStatement(node.Catch),
+			Finally:    cloneBlockStatement(node.Finally),
+		}
+
+	case *FunctionLiteral:
+		if node == nil {
+			return node
+		}
+		return &FunctionLiteral{
+			Token:      node.Token,
+			Parameters: cloneExpressions(node.Parameters),
+			Rest:       cloneIdent(node.Rest),
+			Body:       cloneBlockStatement(node.Body),
+		}
+
+	case *CallExpression:
+		if node == nil {
+			return node
+		}
+		return &CallExpression{
+			Token:     node.Token,
+			Function:  cloneExpression(node.Function),
+			Arguments: cloneExpressions(node.Arguments),
+		}
+
+	case *ArrayLiteral:
+		if node == nil {
+			return node
+		}
+		return &ArrayLiteral{
+			Token:    node.Token,
+			Elements: cloneExpressions(node.Elements),
+		}
+
+	case *IndexExpression:
+		if node == nil {
+			return node
+		}
+		return &IndexExpression{
+			Token: node.Token,
+			Left:  cloneExpression(node.Left),
+			Index: cloneExpression(node.Index),
+		}
+
+	case *SliceExpression:
+		if node == nil {
+			return node
+		}
+		return &SliceExpression{
+			Token: node.Token,
+			Left:  cloneExpression(node.Left),
+			Low:   cloneExpression(node.Low),
+			High:  cloneExpression(node.High),
+		}
+
+	case *MemberExpression:
+		if node == nil {
+			return node
+		}
+		return &MemberExpression{
+			Token:  node.Token,
+			Left:   cloneExpression(node.Left),
+			Member: cloneIdent(node.Member),
+		}
+
+	case *HashLiteral:
+		if node == nil {
+			return node
+		}
+		return &HashLiteral{
+			Token: node.Token,
+			Pairs: cloneHashPairs(node.Pairs),
+		}
+
+	case *SpreadExpression:
+		if node == nil {
+			return node
+		}
+		return &SpreadExpression{
+			Token: node.Token,
+			Value: cloneExpression(node.Value),
+		}
+
+	case *MatchExpression:
+		if node == nil {
+			return node
+		}
+		var arms []MatchArm
+		if node.Arms != nil {
+			arms = make([]MatchArm, len(node.Arms))
+			for i, arm := range node.Arms {
+				arms[i] = MatchArm{
+					Pattern: cloneExpression(arm.Pattern),
+					Guard:   cloneExpression(arm.Guard),
+					Body:    cloneExpression(arm.Body),
+				}
+			}
+		}
+		return &MatchExpression{
+			Token: node.Token,
+			Value: cloneExpression(node.Value),
+			Arms:  arms,
+		}
+
+	case *ArrayPattern:
+		if node == nil {
+			return node
+		}
+		return &ArrayPattern{
+			Token:    node.Token,
+			Elements: cloneExpressions(node.Elements),
+			Rest:     cloneIdent(node.Rest),
+		}
+
+	case *HashPattern:
+		if node == nil {
+			return node
+		}
+		return &HashPattern{
+			Token: node.Token,
+			Pairs: cloneHashPairs(node.Pairs),
+		}
+
+	case *MacroLiteral:
+		if node == nil {
+			return node
+		}
+		return &MacroLiteral{
+			Token:      node.Token,
+			Parameters: cloneIdents(node.Parameters),
+			Body:       cloneBlockStatement(node.Body),
+		}
+
+	default:
+		panic(fmt.Sprintf("ast.Clone: unexpected node type %T", node))
+	}
+}
+
+func cloneStatements(stmts []Statement) []Statement {
+	if stmts == nil {
+		return nil
+	}
+
+	cloned := make([]Statement, len(stmts))
+	for i, stmt := range stmts {
+		if stmt != nil {
+			cloned[i] = Clone(stmt).(Statement)
+		}
+	}
+	return cloned
+}
+
+func cloneExpression(expr Expression) Expression {
+	if expr == nil {
+		return nil
+	}
+	return Clone(expr).(Expression)
+}
+
+func cloneExpressions(exprs []Expression) []Expression {
+	if exprs == nil {
+		return nil
+	}
+
+	cloned := make([]Expression, len(exprs))
+	for i, expr := range exprs {
+		cloned[i] = cloneExpression(expr)
+	}
+	return cloned
+}
+
+func cloneHashPairs(pairs []HashPair) []HashPair {
+	if pairs == nil {
+		return nil
+	}
+
+	cloned := make([]HashPair, len(pairs))
+	for i, pair := range pairs {
+		cloned[i] = HashPair{Key: cloneExpression(pair.Key), Value: cloneExpression(pair.Value)}
+	}
+	return cloned
+}
+
+func cloneBlockStatement(block *BlockStatement) *BlockStatement {
+	if block == nil {
+		return nil
+	}
+	return &BlockStatement{
+		Token:      block.Token,
+		Statements: cloneStatements(block.Statements),
+	}
+}
+
+func cloneIdent(ident *Ident) *Ident {
+	if ident == nil {
+		return nil
+	}
+	cloned := *ident
+	return &cloned
+}
+
+func cloneIdents(idents []*Ident) []*Ident {
+	if idents == nil {
+		return nil
+	}
+
+	cloned := make([]*Ident, len(idents))
+	for i, ident := range idents {
+		cloned[i] = cloneIdent(ident)
+	}
+	return cloned
+}
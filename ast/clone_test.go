@@ -0,0 +1,107 @@
+package ast
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/skatsuta/monkey-interpreter/token"
+)
+
+func TestClone(t *testing.T) {
+	one := createIntLitFunc(1)
+	ident := func(name string) *Ident {
+		return &Ident{Token: token.Token{Type: token.IDENT, Literal: name}, Value: name}
+	}
+	block := func(exprs ...Expression) *BlockStatement {
+		stmts := make([]Statement, 0, len(exprs))
+		for _, expr := range exprs {
+			stmts = append(stmts, &ExpressionStatement{Expression: expr})
+		}
+		return &BlockStatement{Token: token.Token{Type: token.LBRACE, Literal: "{"}, Statements: stmts}
+	}
+
+	tests := []Node{
+		&Program{Statements: []Statement{
+			&LetStatement{Token: token.Token{Type: token.LET, Literal: "let"}, Name: ident("x"), Value: one()},
+			&ReturnStatement{ReturnValue: ident("x")},
+		}},
+		&ExpressionStatement{Expression: &PrefixExpression{Operator: "-", Right: one()}},
+		&InfixExpression{Left: &FloatLiteral{Value: 1.5}, Operator: "*", Right: &StringLiteral{Value: "s"}},
+		&IfExpression{Condition: &Boolean{Value: true}, Consequence: block(one()), Alternative: block()},
+		&IfExpression{Condition: &Boolean{Value: false}, Consequence: block(one())},
+		&FunctionLiteral{Parameters: []Expression{ident("a"), ident("b")}, Body: block(ident("a"))},
+		&FunctionLiteral{
+			Parameters: []Expression{&ArrayPattern{Elements: []Expression{ident("a")}, Rest: ident("b")}},
+			Body:       block(ident("a")),
+		},
+		&FunctionLiteral{Parameters: []Expression{ident("a")}, Rest: ident("r"), Body: block(ident("r"))},
+		&ArrayLiteral{Elements: []Expression{&SpreadExpression{Value: ident("xs")}, one()}},
+		&HashLiteral{Pairs: []HashPair{{Key: &SpreadExpression{Value: ident("h")}}, {Key: one(), Value: one()}}},
+		&LetStatement{Name: &HashPattern{Pairs: []HashPair{{Key: &StringLiteral{Value: "k"}, Value: ident("k")}}}, Value: ident("h")},
+		&MacroLiteral{Parameters: []*Ident{ident("a")}, Body: block(ident("a"))},
+		&CallExpression{Function: ident("f"), Arguments: []Expression{one(), ident("y")}},
+		&IndexExpression{Left: &ArrayLiteral{Elements: []Expression{one()}}, Index: one()},
+		&SliceExpression{Left: ident("s"), Low: one()},
+		&MemberExpression{Left: ident("cfg"), Member: ident("db")},
+		&ConditionalExpression{Condition: &Boolean{Value: true}, Consequence: one(), Alternative: ident("x")},
+		&TryExpression{Block: block(one()), CatchParam: ident("e"), Catch: block(ident("e")), Finally: block()},
+		&TryExpression{Block: block(), Catch: block(one())},
+		&MatchExpression{Value: ident("x"), Arms: []MatchArm{
+			{Pattern: &ArrayPattern{Elements: []Expression{ident("h")}, Rest: ident("t")}, Body: ident("h")},
+			{Pattern: &HashPattern{Pairs: []HashPair{{Key: &StringLiteral{Value: "k"}, Value: one()}}},
+				Guard: &Boolean{Value: true}, Body: one()},
+		}},
+		&ThrowStatement{Token: token.Token{Type: token.THROW, Literal: "throw"}, Value: one()},
+		&HashLiteral{Pairs: []HashPair{{Key: &StringLiteral{Value: "k"}, Value: one()}}},
+	}
+
+	for _, input := range tests {
+		got := Clone(input)
+
+		if !reflect.DeepEqual(got, input) {
+			t.Errorf("expected %#v, but got %#v", input, got)
+		}
+
+		Inspect(input, func(orig Node) bool {
+			Inspect(got, func(copied Node) bool {
+				if orig != nil && copied != nil && reflect.ValueOf(orig).Pointer() == reflect.ValueOf(copied).Pointer() {
+					t.Errorf("clone of %s shares node %T with the original", input, orig)
+				}
+				return true
+			})
+			return true
+		})
+	}
+}
+
+func TestCloneIsIndependent(t *testing.T) {
+	input := &ExpressionStatement{Expression: &CallExpression{
+		Function:  &Ident{Value: "f"},
+		Arguments: []Expression{&IntegerLiteral{Value: 1}},
+	}}
+	want := Clone(input)
+
+	cloned := Clone(input)
+	_, err := Modify(cloned, func(node Node) Node {
+		if i, ok := node.(*IntegerLiteral); ok {
+			i.Value = 2
+		}
+		return node
+	})
+	if err != nil {
+		t.Fatalf("Modify returned an error: %s", err)
+	}
+
+	if !reflect.DeepEqual(input, want) {
+		t.Errorf("modifying a clone changed the original: %#v", input)
+	}
+	if reflect.DeepEqual(cloned, want) {
+		t.Errorf("clone was not modified: %#v", cloned)
+	}
+}
+
+func TestCloneNil(t *testing.T) {
+	if got := Clone(nil); got != nil {
+		t.Errorf("expected nil, but got %#v", got)
+	}
+}
@@ -1,57 +1,361 @@
 package ast
 
+import "fmt"
+
 // ModifierFunc represents a function which modifies a node.
 type ModifierFunc func(Node) Node
 
 // Modify modifies a `node` using `modifier` function.
-func Modify(node Node, modifier ModifierFunc) Node {
+// Children are modified before their parent, and each non-nil child is replaced in place with the
+// node returned by `modifier`. An error is returned if a replacement does not fit into its parent,
+// e.g. an expression is replaced with nil or a block statement with an expression.
+func Modify(node Node, modifier ModifierFunc) (Node, error) {
 	switch node := node.(type) {
 	case *Program:
-		for i, stmt := range node.Statements {
-			node.Statements[i] = Modify(stmt, modifier).(Statement)
+		if err := modifyStatements(node.Statements, modifier); err != nil {
+			return nil, err
 		}
 	case *ExpressionStatement:
-		node.Expression = Modify(node.Expression, modifier).(Expression)
+		expr, err := modifyExpression(node.Expression, modifier)
+		if err != nil {
+			return nil, err
+		}
+		node.Expression = expr
 	case *InfixExpression:
-		node.Left = Modify(node.Left, modifier).(Expression)
-		node.Right = Modify(node.Right, modifier).(Expression)
+		left, err := modifyExpression(node.Left, modifier)
+		if err != nil {
+			return nil, err
+		}
+		right, err := modifyExpression(node.Right, modifier)
+		if err != nil {
+			return nil, err
+		}
+		node.Left, node.Right = left, right
 	case *PrefixExpression:
-		node.Right = Modify(node.Right, modifier).(Expression)
+		right, err := modifyExpression(node.Right, modifier)
+		if err != nil {
+			return nil, err
+		}
+		node.Right = right
 	case *IndexExpression:
-		node.Left = Modify(node.Left, modifier).(Expression)
-		node.Index = Modify(node.Index, modifier).(Expression)
+		left, err := modifyExpression(node.Left, modifier)
+		if err != nil {
+			return nil, err
+		}
+		index, err := modifyExpression(node.Index, modifier)
+		if err != nil {
+			return nil, err
+		}
+		node.Left, node.Index = left, index
+	case *SliceExpression:
+		left, err := modifyExpression(node.Left, modifier)
+		if err != nil {
+			return nil, err
+		}
+		low, err := modifyExpression(node.Low, modifier)
+		if err != nil {
+			return nil, err
+		}
+		high, err := modifyExpression(node.High, modifier)
+		if err != nil {
+			return nil, err
+		}
+		node.Left, node.Low, node.High = left, low, high
+	case *MemberExpression:
+		left, err := modifyExpression(node.Left, modifier)
+		if err != nil {
+			return nil, err
+		}
+		member, err := modifyIdent(node.Member, modifier)
+		if err != nil {
+			return nil, err
+		}
+		node.Left, node.Member = left, member
 	case *IfExpression:
-		node.Condition = Modify(node.Condition, modifier).(Expression)
-		node.Consequence = Modify(node.Consequence, modifier).(*BlockStatement)
-		if node.Alternative != nil {
-			node.Alternative = Modify(node.Alternative, modifier).(*BlockStatement)
+		cond, err := modifyExpression(node.Condition, modifier)
+		if err != nil {
+			return nil, err
+		}
+		conseq, err := modifyBlockStatement(node.Consequence, modifier)
+		if err != nil {
+			return nil, err
+		}
+		alt, err := modifyBlockStatement(node.Alternative, modifier)
+		if err != nil {
+			return nil, err
 		}
+		node.Condition, node.Consequence, node.Alternative = cond, conseq, alt
 	case *BlockStatement:
-		for i, stmt := range node.Statements {
-			node.Statements[i] = Modify(stmt, modifier).(Statement)
+		if err := modifyStatements(node.Statements, modifier); err != nil {
+			return nil, err
 		}
 	case *ReturnStatement:
-		node.ReturnValue = Modify(node.ReturnValue, modifier).(Expression)
+		value, err := modifyExpression(node.ReturnValue, modifier)
+		if err != nil {
+			return nil, err
+		}
+		node.ReturnValue = value
+	case *ThrowStatement:
+		value, err := modifyExpression(node.Value, modifier)
+		if err != nil {
+			return nil, err
+		}
+		node.Value = value
+	case *ConditionalExpression:
+		cond, err := modifyExpression(node.Condition, modifier)
+		if err != nil {
+			return nil, err
+		}
+		conseq, err := modifyExpression(node.Consequence, modifier)
+		if err != nil {
+			return nil, err
+		}
+		alt, err := modifyExpression(node.Alternative, modifier)
+		if err != nil {
+			return nil, err
+		}
+		node.Condition, node.Consequence, node.Alternative = cond, conseq, alt
+	case *TryExpression:
+		block, err := modifyBlockStatement(node.Block, modifier)
+		if err != nil {
+			return nil, err
+		}
+		param, err := modifyIdent(node.CatchParam, modifier)
+		if err != nil {
+			return nil, err
+		}
+		catch, err := modifyBlockStatement(node.Catch, modifier)
+		if err != nil {
+			return nil, err
+		}
+		finally, err := modifyBlockStatement(node.Finally, modifier)
+		if err != nil {
+			return nil, err
+		}
+		node.Block, node.CatchParam, node.Catch, node.Finally = block, param, catch, finally
 	case *LetStatement:
-		node.Value = Modify(node.Value, modifier).(Expression)
+		name, err := modifyBindingTarget(node.Name, modifier)
+		if err != nil {
+			return nil, err
+		}
+		value, err := modifyExpression(node.Value, modifier)
+		if err != nil {
+			return nil, err
+		}
+		node.Name, node.Value = name, value
 	case *FunctionLiteral:
 		for i, param := range node.Parameters {
-			node.Parameters[i] = Modify(param, modifier).(*Ident)
+			newParam, err := modifyBindingTarget(param, modifier)
+			if err != nil {
+				return nil, err
+			}
+			node.Parameters[i] = newParam
+		}
+		rest, err := modifyIdent(node.Rest, modifier)
+		if err != nil {
+			return nil, err
+		}
+		node.Rest = rest
+		body, err := modifyBlockStatement(node.Body, modifier)
+		if err != nil {
+			return nil, err
+		}
+		node.Body = body
+	case *MacroLiteral:
+		if err := modifyIdents(node.Parameters, modifier); err != nil {
+			return nil, err
+		}
+		body, err := modifyBlockStatement(node.Body, modifier)
+		if err != nil {
+			return nil, err
+		}
+		node.Body = body
+	case *CallExpression:
+		function, err := modifyExpression(node.Function, modifier)
+		if err != nil {
+			return nil, err
+		}
+		node.Function = function
+		if err := modifyExpressions(node.Arguments, modifier); err != nil {
+			return nil, err
 		}
-		node.Body = Modify(node.Body, modifier).(*BlockStatement)
 	case *ArrayLiteral:
-		for i, elem := range node.Elements {
-			node.Elements[i] = Modify(elem, modifier).(Expression)
+		if err := modifyExpressions(node.Elements, modifier); err != nil {
+			return nil, err
 		}
 	case *HashLiteral:
-		newPairs := make(map[Expression]Expression, len(node.Pairs))
-		for key, val := range node.Pairs {
-			newKey := Modify(key, modifier).(Expression)
-			newVal := Modify(val, modifier).(Expression)
-			newPairs[newKey] = newVal
+		if err := modifyHashPairs(node.Pairs, modifier); err != nil {
+			return nil, err
+		}
+	case *SpreadExpression:
+		value, err := modifyExpression(node.Value, modifier)
+		if err != nil {
+			return nil, err
+		}
+		node.Value = value
+	case *MatchExpression:
+		value, err := modifyExpression(node.Value, modifier)
+		if err != nil {
+			return nil, err
+		}
+		node.Value = value
+		for i, arm := range node.Arms {
+			pattern, err := modifyExpression(arm.Pattern, modifier)
+			if err != nil {
+				return nil, err
+			}
+			guard, err := modifyExpression(arm.Guard, modifier)
+			if err != nil {
+				return nil, err
+			}
+			body, err := modifyExpression(arm.Body, modifier)
+			if err != nil {
+				return nil, err
+			}
+			node.Arms[i] = MatchArm{Pattern: pattern, Guard: guard, Body: body}
+		}
+	case *ArrayPattern:
+		if err := modifyExpressions(node.Elements, modifier); err != nil {
+			return nil, err
+		}
+		rest, err := modifyIdent(node.Rest, modifier)
+		if err != nil {
+			return nil, err
+		}
+		node.Rest = rest
+	case *HashPattern:
+		if err := modifyHashPairs(node.Pairs, modifier); err != nil {
+			return nil, err
+		}
+	}
+
+	return modifier(node), nil
+}
+
+func modifyStatements(stmts []Statement, modifier ModifierFunc) error {
+	for i, stmt := range stmts {
+		modified, err := Modify(stmt, modifier)
+		if err != nil {
+			return err
+		}
+
+		newStmt, ok := modified.(Statement)
+		if !ok || newStmt == nil {
+			return replaceError("Statement", stmt, modified)
+		}
+		stmts[i] = newStmt
+	}
+	return nil
+}
+
+func modifyExpression(expr Expression, modifier ModifierFunc) (Expression, error) {
+	if expr == nil {
+		return nil, nil
+	}
+
+	modified, err := Modify(expr, modifier)
+	if err != nil {
+		return nil, err
+	}
+
+	newExpr, ok := modified.(Expression)
+	if !ok || newExpr == nil {
+		return nil, replaceError("Expression", expr, modified)
+	}
+	return newExpr, nil
+}
+
+func modifyExpressions(exprs []Expression, modifier ModifierFunc) error {
+	for i, expr := range exprs {
+		newExpr, err := modifyExpression(expr, modifier)
+		if err != nil {
+			return err
 		}
-		node.Pairs = newPairs
+		exprs[i] = newExpr
+	}
+	return nil
+}
+
+// modifyBindingTarget modifies the name of a let statement or a function parameter, which must stay
+// an identifier or a pattern to destructure with.
+func modifyBindingTarget(target Expression, modifier ModifierFunc) (Expression, error) {
+	if target == nil {
+		return nil, nil
+	}
+
+	modified, err := modifyExpression(target, modifier)
+	if err != nil {
+		return nil, err
 	}
 
-	return modifier(node)
+	switch modified.(type) {
+	case *Ident, *ArrayPattern, *HashPattern:
+		return modified, nil
+	default:
+		return nil, replaceError("*ast.Ident, *ast.ArrayPattern or *ast.HashPattern", target, modified)
+	}
+}
+
+func modifyHashPairs(pairs []HashPair, modifier ModifierFunc) error {
+	for i, pair := range pairs {
+		key, err := modifyExpression(pair.Key, modifier)
+		if err != nil {
+			return err
+		}
+		value, err := modifyExpression(pair.Value, modifier)
+		if err != nil {
+			return err
+		}
+		pairs[i] = HashPair{Key: key, Value: value}
+	}
+	return nil
+}
+
+func modifyBlockStatement(block *BlockStatement, modifier ModifierFunc) (*BlockStatement, error) {
+	if block == nil {
+		return nil, nil
+	}
+
+	modified, err := Modify(block, modifier)
+	if err != nil {
+		return nil, err
+	}
+
+	newBlock, ok := modified.(*BlockStatement)
+	if !ok || newBlock == nil {
+		return nil, replaceError("*ast.BlockStatement", block, modified)
+	}
+	return newBlock, nil
+}
+
+func modifyIdent(ident *Ident, modifier ModifierFunc) (*Ident, error) {
+	if ident == nil {
+		return nil, nil
+	}
+
+	modified, err := Modify(ident, modifier)
+	if err != nil {
+		return nil, err
+	}
+
+	newIdent, ok := modified.(*Ident)
+	if !ok || newIdent == nil {
+		return nil, replaceError("*ast.Ident", ident, modified)
+	}
+	return newIdent, nil
+}
+
+func modifyIdents(idents []*Ident, modifier ModifierFunc) error {
+	for i, ident := range idents {
+		newIdent, err := modifyIdent(ident, modifier)
+		if err != nil {
+			return err
+		}
+		idents[i] = newIdent
+	}
+	return nil
+}
+
+func replaceError(want string, orig, got Node) error {
+	return fmt.Errorf("cannot replace %T with %T: want %s", orig, got, want)
 }
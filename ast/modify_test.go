@@ -3,6 +3,8 @@ package ast
 import (
 	"reflect"
 	"testing"
+
+	"github.com/skatsuta/monkey-interpreter/token"
 )
 
 func createIntLitFunc(i int64) func() Expression {
@@ -83,15 +85,29 @@ func TestModify(t *testing.T) {
 			input: &LetStatement{Value: one()},
 			want:  &LetStatement{Value: two()},
 		},
+		{
+			input: &LetStatement{Name: &ArrayPattern{Elements: []Expression{one()}}, Value: one()},
+			want:  &LetStatement{Name: &ArrayPattern{Elements: []Expression{two()}}, Value: two()},
+		},
 		{
 			input: &FunctionLiteral{
-				Parameters: []*Ident{},
+				Parameters: []Expression{&HashPattern{Pairs: []HashPair{{Key: one(), Value: &Ident{Value: "a"}}}}},
+				Body:       &BlockStatement{},
+			},
+			want: &FunctionLiteral{
+				Parameters: []Expression{&HashPattern{Pairs: []HashPair{{Key: two(), Value: &Ident{Value: "a"}}}}},
+				Body:       &BlockStatement{},
+			},
+		},
+		{
+			input: &FunctionLiteral{
+				Parameters: []Expression{},
 				Body: &BlockStatement{
 					Statements: []Statement{&ExpressionStatement{Expression: one()}},
 				},
 			},
 			want: &FunctionLiteral{
-				Parameters: []*Ident{},
+				Parameters: []Expression{},
 				Body: &BlockStatement{
 					Statements: []Statement{&ExpressionStatement{Expression: two()}},
 				},
@@ -101,10 +117,158 @@ func TestModify(t *testing.T) {
 			input: &ArrayLiteral{Elements: []Expression{one(), one()}},
 			want:  &ArrayLiteral{Elements: []Expression{two(), two()}},
 		},
+		{
+			input: &ExpressionStatement{Expression: &CallExpression{
+				Function:  &FunctionLiteral{Parameters: []Expression{}, Body: &BlockStatement{}},
+				Arguments: []Expression{one(), two(), one()},
+			}},
+			want: &ExpressionStatement{Expression: &CallExpression{
+				Function:  &FunctionLiteral{Parameters: []Expression{}, Body: &BlockStatement{}},
+				Arguments: []Expression{two(), two(), two()},
+			}},
+		},
+		{
+			input: &CallExpression{
+				Function: &IndexExpression{
+					Left:  &ArrayLiteral{Elements: []Expression{}},
+					Index: one(),
+				},
+				Arguments: []Expression{},
+			},
+			want: &CallExpression{
+				Function: &IndexExpression{
+					Left:  &ArrayLiteral{Elements: []Expression{}},
+					Index: two(),
+				},
+				Arguments: []Expression{},
+			},
+		},
+		{
+			input: &MacroLiteral{
+				Parameters: []*Ident{},
+				Body: &BlockStatement{
+					Statements: []Statement{&ExpressionStatement{Expression: one()}},
+				},
+			},
+			want: &MacroLiteral{
+				Parameters: []*Ident{},
+				Body: &BlockStatement{
+					Statements: []Statement{&ExpressionStatement{Expression: two()}},
+				},
+			},
+		},
+		{
+			input: &IfExpression{
+				Condition: &Boolean{Value: true},
+				Consequence: &BlockStatement{
+					Statements: []Statement{&ReturnStatement{ReturnValue: one()}},
+				},
+			},
+			want: &IfExpression{
+				Condition: &Boolean{Value: true},
+				Consequence: &BlockStatement{
+					Statements: []Statement{&ReturnStatement{ReturnValue: two()}},
+				},
+			},
+		},
+		{
+			input: &SliceExpression{Left: one(), Low: one(), High: one()},
+			want:  &SliceExpression{Left: two(), Low: two(), High: two()},
+		},
+		{
+			input: &SliceExpression{Left: one(), High: one()},
+			want:  &SliceExpression{Left: two(), High: two()},
+		},
+		{
+			input: &MemberExpression{Left: one(), Member: &Ident{Value: "m"}},
+			want:  &MemberExpression{Left: two(), Member: &Ident{Value: "m"}},
+		},
+		{
+			input: &ConditionalExpression{Condition: one(), Consequence: one(), Alternative: one()},
+			want:  &ConditionalExpression{Condition: two(), Consequence: two(), Alternative: two()},
+		},
+		{
+			input: &ThrowStatement{Value: one()},
+			want:  &ThrowStatement{Value: two()},
+		},
+		{
+			input: &TryExpression{
+				Block: &BlockStatement{
+					Statements: []Statement{&ExpressionStatement{Expression: one()}},
+				},
+				CatchParam: &Ident{Value: "e"},
+				Catch: &BlockStatement{
+					Statements: []Statement{&ExpressionStatement{Expression: one()}},
+				},
+				Finally: &BlockStatement{
+					Statements: []Statement{&ExpressionStatement{Expression: one()}},
+				},
+			},
+			want: &TryExpression{
+				Block: &BlockStatement{
+					Statements: []Statement{&ExpressionStatement{Expression: two()}},
+				},
+				CatchParam: &Ident{Value: "e"},
+				Catch: &BlockStatement{
+					Statements: []Statement{&ExpressionStatement{Expression: two()}},
+				},
+				Finally: &BlockStatement{
+					Statements: []Statement{&ExpressionStatement{Expression: two()}},
+				},
+			},
+		},
+		{
+			input: &TryExpression{Block: &BlockStatement{}, Finally: &BlockStatement{
+				Statements: []Statement{&ThrowStatement{Value: one()}},
+			}},
+			want: &TryExpression{Block: &BlockStatement{}, Finally: &BlockStatement{
+				Statements: []Statement{&ThrowStatement{Value: two()}},
+			}},
+		},
+		{
+			input: &MatchExpression{
+				Value: one(),
+				Arms: []MatchArm{
+					{Pattern: one(), Body: one()},
+					{Pattern: &Ident{Value: "n"}, Guard: one(), Body: one()},
+				},
+			},
+			want: &MatchExpression{
+				Value: two(),
+				Arms: []MatchArm{
+					{Pattern: two(), Body: two()},
+					{Pattern: &Ident{Value: "n"}, Guard: two(), Body: two()},
+				},
+			},
+		},
+		{
+			input: &CallExpression{Function: &Ident{Value: "f"}, Arguments: []Expression{&SpreadExpression{Value: one()}}},
+			want:  &CallExpression{Function: &Ident{Value: "f"}, Arguments: []Expression{&SpreadExpression{Value: two()}}},
+		},
+		{
+			input: &HashLiteral{Pairs: []HashPair{{Key: &SpreadExpression{Value: one()}}}},
+			want:  &HashLiteral{Pairs: []HashPair{{Key: &SpreadExpression{Value: two()}}}},
+		},
+		{
+			input: &ArrayPattern{Elements: []Expression{one(), &Ident{Value: "a"}}, Rest: &Ident{Value: "r"}},
+			want:  &ArrayPattern{Elements: []Expression{two(), &Ident{Value: "a"}}, Rest: &Ident{Value: "r"}},
+		},
+		{
+			input: &HashPattern{Pairs: []HashPair{{Key: one(), Value: one()}}},
+			want:  &HashPattern{Pairs: []HashPair{{Key: two(), Value: two()}}},
+		},
+		{
+			input: &StringLiteral{Value: "1"},
+			want:  &StringLiteral{Value: "1"},
+		},
 	}
 
 	for _, tt := range tests {
-		got := Modify(tt.input, turnOneIntoTwo)
+		got, err := Modify(tt.input, turnOneIntoTwo)
+		if err != nil {
+			t.Errorf("Modify(%#v) returned an error: %s", tt.input, err)
+			continue
+		}
 
 		if !reflect.DeepEqual(got, tt.want) {
 			t.Errorf("expected %#v, but got %#v", tt.want, got)
@@ -114,22 +278,106 @@ func TestModify(t *testing.T) {
 	// Test for hash literals
 
 	hashLit := &HashLiteral{
-		Pairs: map[Expression]Expression{
-			one(): one(),
-			one(): one(),
+		Pairs: []HashPair{
+			{Key: one(), Value: one()},
+			{Key: one(), Value: one()},
 		},
 	}
 
-	Modify(hashLit, turnOneIntoTwo)
+	if _, err := Modify(hashLit, turnOneIntoTwo); err != nil {
+		t.Fatalf("Modify(%#v) returned an error: %s", hashLit, err)
+	}
 
-	for key, val := range hashLit.Pairs {
-		key := key.(*IntegerLiteral)
+	for _, pair := range hashLit.Pairs {
+		key := pair.Key.(*IntegerLiteral)
 		if key.Value != 2 {
 			t.Errorf("key is not %d and got %d", 2, key.Value)
 		}
-		val := val.(*IntegerLiteral)
+		val := pair.Value.(*IntegerLiteral)
 		if val.Value != 2 {
-			t.Errorf("value is not %d and got %d", 2, key.Value)
+			t.Errorf("value is not %d and got %d", 2, val.Value)
+		}
+	}
+}
+
+func TestModifyRenamesIdents(t *testing.T) {
+	ident := func(name string) *Ident { return &Ident{Value: name} }
+	letTok := token.Token{Type: token.LET, Literal: "let"}
+	fnTok := token.Token{Type: token.FUNCTION, Literal: "fn"}
+
+	rename := func(node Node) Node {
+		if i, ok := node.(*Ident); ok && i.Value == "a" {
+			return ident("b")
+		}
+		return node
+	}
+
+	input := &Program{Statements: []Statement{
+		&LetStatement{Token: letTok, Name: ident("a"), Value: &FunctionLiteral{
+			Token:      fnTok,
+			Parameters: []Expression{ident("a"), ident("c")},
+			Body: &BlockStatement{
+				Statements: []Statement{&ExpressionStatement{Expression: &CallExpression{
+					Function:  ident("a"),
+					Arguments: []Expression{ident("a"), ident("c")},
+				}}},
+			},
+		}},
+	}}
+
+	got, err := Modify(input, rename)
+	if err != nil {
+		t.Fatalf("Modify returned an error: %s", err)
+	}
+
+	want := "let b = fn(b, c) b(b, c);"
+	if got.String() != want {
+		t.Errorf("expected %q, but got %q", want, got.String())
+	}
+}
+
+func TestModifyErrors(t *testing.T) {
+	one := createIntLitFunc(1)
+
+	intoNil := func(node Node) Node {
+		if i, ok := node.(*IntegerLiteral); ok && i.Value == 1 {
+			return nil
+		}
+		return node
+	}
+
+	intoStatement := func(node Node) Node {
+		switch node.(type) {
+		case *IntegerLiteral, *BlockStatement:
+			return &ExpressionStatement{Expression: &Boolean{}}
+		}
+		return node
+	}
+
+	intoExpression := func(node Node) Node {
+		if _, ok := node.(*Ident); ok {
+			return &IntegerLiteral{Value: 3}
+		}
+		return node
+	}
+
+	tests := []struct {
+		input    Node
+		modifier ModifierFunc
+	}{
+		{&ExpressionStatement{Expression: one()}, intoNil},
+		{&Program{Statements: []Statement{&ExpressionStatement{Expression: one()}}}, intoNil},
+		{&CallExpression{Function: &Ident{Value: "f"}, Arguments: []Expression{one()}}, intoNil},
+		{&ArrayLiteral{Elements: []Expression{one()}}, intoStatement},
+		{&IfExpression{Condition: &Boolean{}, Consequence: &BlockStatement{}}, intoStatement},
+		{&FunctionLiteral{Parameters: []Expression{&Ident{Value: "x"}}, Body: &BlockStatement{}}, intoExpression},
+		{&LetStatement{Name: &Ident{Value: "x"}, Value: &Boolean{}}, intoExpression},
+		{&HashLiteral{Pairs: []HashPair{{Key: one(), Value: &Boolean{}}}}, intoNil},
+	}
+
+	for _, tt := range tests {
+		if got, err := Modify(tt.input, tt.modifier); err == nil {
+			t.Errorf("expected an error for %#v, but got %#v", tt.input, got)
 		}
 	}
 }
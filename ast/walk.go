@@ -0,0 +1,196 @@
+package ast
+
+import "fmt"
+
+// Visitor is the interface that wraps the Visit method used by Walk.
+//
+// Visit is called when Walk enters a node. If the returned visitor w is not nil, Walk visits each
+// of the children of the node with w, followed by a call of w.Visit(nil) when it leaves the node.
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses an AST in depth-first order. It starts by calling v.Visit(node); node must not be
+// nil. If the visitor w returned by v.Visit(node) is not nil, Walk is invoked recursively with
+// visitor w for each of the non-nil children of node, followed by a call of w.Visit(nil).
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	// Statements
+
+	case *Program:
+		walkStatements(v, n.Statements)
+
+	case *LetStatement:
+		if n.Name != nil {
+			Walk(v, n.Name)
+		}
+		if n.Value != nil {
+			Walk(v, n.Value)
+		}
+
+	case *ReturnStatement:
+		if n.ReturnValue != nil {
+			Walk(v, n.ReturnValue)
+		}
+
+	case *ThrowStatement:
+		if n.Value != nil {
+			Walk(v, n.Value)
+		}
+
+	case *ExpressionStatement:
+		if n.Expression != nil {
+			Walk(v, n.Expression)
+		}
+
+	case *BlockStatement:
+		walkStatements(v, n.Statements)
+
+	// Expressions
+
+	case *Ident, *IntegerLiteral, *FloatLiteral, *Boolean, *StringLiteral:
+		// nothing to do
+
+	case *PrefixExpression:
+		Walk(v, n.Right)
+
+	case *InfixExpression:
+		Walk(v, n.Left)
+		Walk(v, n.Right)
+
+	case *IfExpression:
+		Walk(v, n.Condition)
+		Walk(v, n.Consequence)
+		if n.Alternative != nil {
+			Walk(v, n.Alternative)
+		}
+
+	case *ConditionalExpression:
+		Walk(v, n.Condition)
+		Walk(v, n.Consequence)
+		Walk(v, n.Alternative)
+
+	case *TryExpression:
+		Walk(v, n.Block)
+		if n.CatchParam != nil {
+			Walk(v, n.CatchParam)
+		}
+		if n.Catch != nil {
+			Walk(v, n.Catch)
+		}
+		if n.Finally != nil {
+			Walk(v, n.Finally)
+		}
+
+	case *FunctionLiteral:
+		walkExpressions(v, n.Parameters)
+		if n.Rest != nil {
+			Walk(v, n.Rest)
+		}
+		Walk(v, n.Body)
+
+	case *CallExpression:
+		Walk(v, n.Function)
+		walkExpressions(v, n.Arguments)
+
+	case *ArrayLiteral:
+		walkExpressions(v, n.Elements)
+
+	case *IndexExpression:
+		Walk(v, n.Left)
+		Walk(v, n.Index)
+
+	case *SliceExpression:
+		Walk(v, n.Left)
+		if n.Low != nil {
+			Walk(v, n.Low)
+		}
+		if n.High != nil {
+			Walk(v, n.High)
+		}
+
+	case *MemberExpression:
+		Walk(v, n.Left)
+		Walk(v, n.Member)
+
+	case *HashLiteral:
+		for _, pair := range n.Pairs {
+			Walk(v, pair.Key)
+			if pair.Value != nil {
+				Walk(v, pair.Value)
+			}
+		}
+
+	case *SpreadExpression:
+		Walk(v, n.Value)
+
+	case *MatchExpression:
+		Walk(v, n.Value)
+		for _, arm := range n.Arms {
+			Walk(v, arm.Pattern)
+			if arm.Guard != nil {
+				Walk(v, arm.Guard)
+			}
+			Walk(v, arm.Body)
+		}
+
+	case *ArrayPattern:
+		walkExpressions(v, n.Elements)
+		if n.Rest != nil {
+			Walk(v, n.Rest)
+		}
+
+	case *HashPattern:
+		for _, pair := range n.Pairs {
+			Walk(v, pair.Key)
+			Walk(v, pair.Value)
+		}
+
+	case *MacroLiteral:
+		walkIdents(v, n.Parameters)
+		Walk(v, n.Body)
+
+	default:
+		panic(fmt.Sprintf("ast.Walk: unexpected node type %T", n))
+	}
+
+	v.Visit(nil)
+}
+
+func walkStatements(v Visitor, stmts []Statement) {
+	for _, stmt := range stmts {
+		Walk(v, stmt)
+	}
+}
+
+func walkExpressions(v Visitor, exprs []Expression) {
+	for _, expr := range exprs {
+		Walk(v, expr)
+	}
+}
+
+func walkIdents(v Visitor, idents []*Ident) {
+	for _, ident := range idents {
+		Walk(v, ident)
+	}
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses an AST in depth-first order. It starts by calling f(node); node must not be nil.
+// If f returns true, Inspect invokes f recursively for each of the non-nil children of node,
+// followed by a call of f(nil).
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
@@ -0,0 +1,225 @@
+package ast
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestInspect(t *testing.T) {
+	ident := func(name string) *Ident { return &Ident{Value: name} }
+	one := createIntLitFunc(1)
+
+	tests := []struct {
+		input Node
+		want  []string
+	}{
+		{
+			input: &Program{Statements: []Statement{
+				&LetStatement{Name: ident("x"), Value: one()},
+				&ReturnStatement{ReturnValue: ident("x")},
+			}},
+			want: []string{
+				"*ast.Program",
+				"*ast.LetStatement", "*ast.Ident", "*ast.IntegerLiteral",
+				"*ast.ReturnStatement", "*ast.Ident",
+			},
+		},
+		{
+			input: &ExpressionStatement{Expression: &PrefixExpression{
+				Operator: "-",
+				Right:    &InfixExpression{Left: one(), Operator: "+", Right: &FloatLiteral{}},
+			}},
+			want: []string{
+				"*ast.ExpressionStatement", "*ast.PrefixExpression", "*ast.InfixExpression",
+				"*ast.IntegerLiteral", "*ast.FloatLiteral",
+			},
+		},
+		{
+			input: &IfExpression{
+				Condition:   &Boolean{Value: true},
+				Consequence: &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: one()}}},
+				Alternative: &BlockStatement{},
+			},
+			want: []string{
+				"*ast.IfExpression", "*ast.Boolean", "*ast.BlockStatement",
+				"*ast.ExpressionStatement", "*ast.IntegerLiteral", "*ast.BlockStatement",
+			},
+		},
+		{
+			input: &CallExpression{
+				Function: &FunctionLiteral{
+					Parameters: []Expression{ident("a")},
+					Body:       &BlockStatement{},
+				},
+				Arguments: []Expression{&StringLiteral{Value: "s"}, one()},
+			},
+			want: []string{
+				"*ast.CallExpression", "*ast.FunctionLiteral", "*ast.Ident", "*ast.BlockStatement",
+				"*ast.StringLiteral", "*ast.IntegerLiteral",
+			},
+		},
+		{
+			input: &IndexExpression{
+				Left:  &ArrayLiteral{Elements: []Expression{one()}},
+				Index: one(),
+			},
+			want: []string{
+				"*ast.IndexExpression", "*ast.ArrayLiteral", "*ast.IntegerLiteral",
+				"*ast.IntegerLiteral",
+			},
+		},
+		{
+			input: &SliceExpression{Left: ident("s"), High: one()},
+			want:  []string{"*ast.SliceExpression", "*ast.Ident", "*ast.IntegerLiteral"},
+		},
+		{
+			input: &HashLiteral{Pairs: []HashPair{
+				{Key: ident("k"), Value: one()},
+				{Key: &StringLiteral{Value: "s"}, Value: &Boolean{}},
+			}},
+			want: []string{
+				"*ast.HashLiteral", "*ast.Ident", "*ast.IntegerLiteral",
+				"*ast.StringLiteral", "*ast.Boolean",
+			},
+		},
+		{
+			input: &MemberExpression{Left: ident("cfg"), Member: ident("db")},
+			want:  []string{"*ast.MemberExpression", "*ast.Ident", "*ast.Ident"},
+		},
+		{
+			input: &ConditionalExpression{Condition: &Boolean{}, Consequence: one(), Alternative: ident("x")},
+			want:  []string{"*ast.ConditionalExpression", "*ast.Boolean", "*ast.IntegerLiteral", "*ast.Ident"},
+		},
+		{
+			input: &TryExpression{
+				Block:      &BlockStatement{Statements: []Statement{&ThrowStatement{Value: one()}}},
+				CatchParam: ident("e"),
+				Catch:      &BlockStatement{},
+				Finally:    &BlockStatement{},
+			},
+			want: []string{
+				"*ast.TryExpression", "*ast.BlockStatement", "*ast.ThrowStatement",
+				"*ast.IntegerLiteral", "*ast.Ident", "*ast.BlockStatement", "*ast.BlockStatement",
+			},
+		},
+		{
+			input: &TryExpression{Block: &BlockStatement{}, Finally: &BlockStatement{}},
+			want:  []string{"*ast.TryExpression", "*ast.BlockStatement", "*ast.BlockStatement"},
+		},
+		{
+			input: &MatchExpression{
+				Value: ident("x"),
+				Arms: []MatchArm{
+					{Pattern: one(), Body: one()},
+					{Pattern: ident("n"), Guard: &Boolean{}, Body: ident("n")},
+				},
+			},
+			want: []string{
+				"*ast.MatchExpression", "*ast.Ident", "*ast.IntegerLiteral", "*ast.IntegerLiteral",
+				"*ast.Ident", "*ast.Boolean", "*ast.Ident",
+			},
+		},
+		{
+			input: &FunctionLiteral{Parameters: []Expression{ident("a")}, Rest: ident("r"), Body: &BlockStatement{}},
+			want:  []string{"*ast.FunctionLiteral", "*ast.Ident", "*ast.Ident", "*ast.BlockStatement"},
+		},
+		{
+			input: &HashLiteral{Pairs: []HashPair{
+				{Key: &SpreadExpression{Value: ident("h")}},
+				{Key: ident("k"), Value: one()},
+			}},
+			want: []string{
+				"*ast.HashLiteral", "*ast.SpreadExpression", "*ast.Ident", "*ast.Ident",
+				"*ast.IntegerLiteral",
+			},
+		},
+		{
+			input: &ArrayPattern{Elements: []Expression{one()}, Rest: ident("r")},
+			want:  []string{"*ast.ArrayPattern", "*ast.IntegerLiteral", "*ast.Ident"},
+		},
+		{
+			input: &HashPattern{Pairs: []HashPair{{Key: &StringLiteral{Value: "k"}, Value: ident("v")}}},
+			want:  []string{"*ast.HashPattern", "*ast.StringLiteral", "*ast.Ident"},
+		},
+		{
+			input: &MacroLiteral{Parameters: []*Ident{ident("a")}, Body: &BlockStatement{}},
+			want:  []string{"*ast.MacroLiteral", "*ast.Ident", "*ast.BlockStatement"},
+		},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		Inspect(tt.input, func(node Node) bool {
+			if node != nil {
+				got = append(got, fmt.Sprintf("%T", node))
+			}
+			return true
+		})
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("expected %v, but got %v", tt.want, got)
+		}
+	}
+}
+
+func TestInspectSkipsChildren(t *testing.T) {
+	input := &InfixExpression{
+		Left:     &PrefixExpression{Operator: "-", Right: &IntegerLiteral{Value: 1}},
+		Operator: "+",
+		Right:    &IntegerLiteral{Value: 2},
+	}
+
+	var got []string
+	Inspect(input, func(node Node) bool {
+		if node == nil {
+			return false
+		}
+		got = append(got, fmt.Sprintf("%T", node))
+		_, isPrefix := node.(*PrefixExpression)
+		return !isPrefix
+	})
+
+	want := []string{"*ast.InfixExpression", "*ast.PrefixExpression", "*ast.IntegerLiteral"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, but got %v", want, got)
+	}
+}
+
+type depthVisitor struct {
+	depth    *int
+	maxDepth *int
+}
+
+func (v depthVisitor) Visit(node Node) Visitor {
+	if node == nil {
+		*v.depth--
+		return nil
+	}
+
+	*v.depth++
+	if *v.depth > *v.maxDepth {
+		*v.maxDepth = *v.depth
+	}
+	return v
+}
+
+func TestWalkEnterLeave(t *testing.T) {
+	input := &Program{Statements: []Statement{
+		&ExpressionStatement{Expression: &InfixExpression{
+			Left:     &IntegerLiteral{Value: 1},
+			Operator: "+",
+			Right:    &InfixExpression{Left: &IntegerLiteral{Value: 2}, Operator: "*", Right: &Ident{Value: "x"}},
+		}},
+	}}
+
+	var depth, maxDepth int
+	Walk(depthVisitor{depth: &depth, maxDepth: &maxDepth}, input)
+
+	if depth != 0 {
+		t.Errorf("enter and leave calls are unbalanced: depth=%d", depth)
+	}
+	if want := 5; maxDepth != want {
+		t.Errorf("expected max depth %d, but got %d", want, maxDepth)
+	}
+}
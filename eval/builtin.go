@@ -1,7 +1,8 @@
 package eval
 
 import (
-	"fmt"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/skatsuta/monkey-interpreter/object"
 )
@@ -15,7 +16,8 @@ var builtins = map[string]*object.Builtin{
 
 			switch arg := args[0].(type) {
 			case *object.String:
-				return &object.Integer{Value: int64(len(arg.Value))}
+				// Count characters rather than bytes, like indices of strings.
+				return &object.Integer{Value: int64(utf8.RuneCountInString(arg.Value))}
 			case *object.Array:
 				return &object.Integer{Value: int64(len(arg.Elements))}
 			default:
@@ -103,12 +105,62 @@ var builtins = map[string]*object.Builtin{
 		},
 	},
 
-	"puts": {
+	"compare": {
 		Fn: func(args ...object.Object) object.Object {
-			for _, arg := range args {
-				fmt.Println(arg.Inspect())
+			if l := len(args); l != 2 {
+				return newError("wrong number of arguments. want=2, got=%d", l)
+			}
+
+			c, err := object.Compare(args[0], args[1])
+			if err != nil {
+				return newError("%s", err)
+			}
+			return &object.Integer{Value: int64(c)}
+		},
+	},
+
+	"collate": {
+		Fn: func(args ...object.Object) object.Object {
+			if l := len(args); l != 2 {
+				return newError("wrong number of arguments. want=2, got=%d", l)
+			}
+
+			a, ok := args[0].(*object.String)
+			if !ok {
+				return newError("first argument to `collate` must be String, got %s", args[0].Type())
+			}
+			b, ok := args[1].(*object.String)
+			if !ok {
+				return newError("second argument to `collate` must be String, got %s", args[1].Type())
 			}
-			return NilValue
+			return &object.Integer{Value: int64(collate(a.Value, b.Value))}
 		},
 	},
 }
+
+// envBuiltins are builtins which depend on the environment in which they are called, e.g. for its
+// output. evalIdent binds them to the environment in which they are looked up.
+var envBuiltins = map[string]func(env object.Environment) object.BuiltinFunction{}
+
+// isBuiltin reports whether `name` is the name of a builtin or a constant defined by the
+// interpreter.
+func isBuiltin(name string) bool {
+	if _, ok := builtins[name]; ok {
+		return true
+	}
+	if _, ok := envBuiltins[name]; ok {
+		return true
+	}
+	_, ok := constants[name]
+	return ok
+}
+
+// collate compares two strings for sorting text meant for humans. Unlike the byte-wise ordering of
+// the comparison operators, letters are compared case-insensitively by Unicode case mapping first,
+// and only strings differing in case alone are ordered byte-wise.
+func collate(a, b string) int {
+	if c := strings.Compare(strings.ToLower(a), strings.ToLower(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,505 @@
+package eval
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/skatsuta/monkey-interpreter/object"
+)
+
+// Collection builtins call back into Monkey functions through applyFunction, which in turn refers to
+// builtins, so they are registered in init to avoid an initialization cycle.
+func init() {
+	for name, fn := range map[string]object.BuiltinFunction{
+		"map":       builtinMap,
+		"filter":    builtinFilter,
+		"reduce":    builtinReduce,
+		"each":      builtinEach,
+		"sort":      builtinSort,
+		"reverse":   builtinReverse,
+		"zip":       builtinZip,
+		"flatten":   builtinFlatten,
+		"uniq":      builtinUniq,
+		"any":       builtinAny,
+		"all":       builtinAll,
+		"find":      builtinFind,
+		"range":     builtinRange,
+		"enumerate": builtinEnumerate,
+	} {
+		builtins[name] = &object.Builtin{Fn: fn}
+	}
+}
+
+// checkArgs returns an error if the number of `args` is not between `min` and `max` inclusive.
+func checkArgs(args []object.Object, min, max int) *object.Error {
+	l := len(args)
+	if min <= l && l <= max {
+		return nil
+	}
+
+	switch {
+	case min == max:
+		return newError("wrong number of arguments. want=%d, got=%d", min, l)
+	case min+1 == max:
+		return newError("wrong number of arguments. want=%d or %d, got=%d", min, max, l)
+	default:
+		return newError("wrong number of arguments. want=%d..%d, got=%d", min, max, l)
+	}
+}
+
+// arrayArg returns the argument at `pos` as an array, or an error mentioning the builtin `name`.
+func arrayArg(name string, args []object.Object, pos int) (*object.Array, *object.Error) {
+	arr, ok := args[pos].(*object.Array)
+	if !ok {
+		return nil, newError("%s argument to `%s` must be Array, got %s", ordinal(pos), name, args[pos].Type())
+	}
+	return arr, nil
+}
+
+// functionArg returns the argument at `pos` if it can be called, or an error mentioning the
+// builtin `name`.
+func functionArg(name string, args []object.Object, pos int) (object.Object, *object.Error) {
+	switch fn := args[pos].(type) {
+	case *object.Function, *object.Builtin:
+		return fn, nil
+	default:
+		return nil, newError("%s argument to `%s` must be Function, got %s", ordinal(pos), name, fn.Type())
+	}
+}
+
+func ordinal(pos int) string {
+	switch pos {
+	case 0:
+		return "first"
+	case 1:
+		return "second"
+	case 2:
+		return "third"
+	case 3:
+		return "fourth"
+	default:
+		return fmt.Sprintf("#%d", pos+1)
+	}
+}
+
+func builtinMap(args ...object.Object) object.Object {
+	if err := checkArgs(args, 2, 2); err != nil {
+		return err
+	}
+	arr, err := arrayArg("map", args, 0)
+	if err != nil {
+		return err
+	}
+	fn, err := functionArg("map", args, 1)
+	if err != nil {
+		return err
+	}
+
+	elems := make([]object.Object, 0, len(arr.Elements))
+	for _, elem := range arr.Elements {
+		mapped := applyFunction(fn, []object.Object{elem})
+		if isError(mapped) {
+			return mapped
+		}
+		elems = append(elems, mapped)
+	}
+	return &object.Array{Elements: elems}
+}
+
+func builtinFilter(args ...object.Object) object.Object {
+	if err := checkArgs(args, 2, 2); err != nil {
+		return err
+	}
+	arr, err := arrayArg("filter", args, 0)
+	if err != nil {
+		return err
+	}
+	fn, err := functionArg("filter", args, 1)
+	if err != nil {
+		return err
+	}
+
+	elems := make([]object.Object, 0, len(arr.Elements))
+	for _, elem := range arr.Elements {
+		ok := applyFunction(fn, []object.Object{elem})
+		if isError(ok) {
+			return ok
+		}
+		if isTruthy(ok) {
+			elems = append(elems, elem)
+		}
+	}
+	return &object.Array{Elements: elems}
+}
+
+// builtinReduce implements `reduce(array, fn(acc, elem), initial)`. Without an initial value, the
+// first element is used instead.
+func builtinReduce(args ...object.Object) object.Object {
+	if err := checkArgs(args, 2, 3); err != nil {
+		return err
+	}
+	arr, err := arrayArg("reduce", args, 0)
+	if err != nil {
+		return err
+	}
+	fn, err := functionArg("reduce", args, 1)
+	if err != nil {
+		return err
+	}
+
+	elems := arr.Elements
+	var acc object.Object
+	if len(args) == 3 {
+		acc = args[2]
+	} else {
+		if len(elems) == 0 {
+			return newError("`reduce` of empty Array with no initial value")
+		}
+		acc, elems = elems[0], elems[1:]
+	}
+
+	for _, elem := range elems {
+		acc = applyFunction(fn, []object.Object{acc, elem})
+		if isError(acc) {
+			return acc
+		}
+	}
+	return acc
+}
+
+func builtinEach(args ...object.Object) object.Object {
+	if err := checkArgs(args, 2, 2); err != nil {
+		return err
+	}
+	arr, err := arrayArg("each", args, 0)
+	if err != nil {
+		return err
+	}
+	fn, err := functionArg("each", args, 1)
+	if err != nil {
+		return err
+	}
+
+	for _, elem := range arr.Elements {
+		if result := applyFunction(fn, []object.Object{elem}); isError(result) {
+			return result
+		}
+	}
+	return NilValue
+}
+
+// builtinSort implements `sort(array, comparator)`. It returns a new array sorted in ascending order
+// of object.Compare, or of the optional comparator which returns a negative integer, zero or a
+// positive integer like the `compare` builtin. The sort is stable.
+func builtinSort(args ...object.Object) object.Object {
+	if err := checkArgs(args, 1, 2); err != nil {
+		return err
+	}
+	arr, err := arrayArg("sort", args, 0)
+	if err != nil {
+		return err
+	}
+
+	var cmp object.Object
+	if len(args) == 2 {
+		if cmp, err = functionArg("sort", args, 1); err != nil {
+			return err
+		}
+	}
+
+	elems := make([]object.Object, len(arr.Elements))
+	copy(elems, arr.Elements)
+
+	var sortErr *object.Error
+	sort.SliceStable(elems, func(i, j int) bool {
+		if sortErr != nil {
+			return false
+		}
+
+		if cmp == nil {
+			c, err := object.Compare(elems[i], elems[j])
+			if err != nil {
+				sortErr = newError("%s", err)
+			}
+			return c < 0
+		}
+
+		result := applyFunction(cmp, []object.Object{elems[i], elems[j]})
+		switch result := result.(type) {
+		case *object.Integer:
+			return result.Value < 0
+		case *object.Error:
+			sortErr = result
+		default:
+			sortErr = newError("comparator of `sort` must return Integer, got %s", result.Type())
+		}
+		return false
+	})
+
+	if sortErr != nil {
+		return sortErr
+	}
+	return &object.Array{Elements: elems}
+}
+
+func builtinReverse(args ...object.Object) object.Object {
+	if err := checkArgs(args, 1, 1); err != nil {
+		return err
+	}
+
+	switch arg := args[0].(type) {
+	case *object.Array:
+		l := len(arg.Elements)
+		elems := make([]object.Object, l)
+		for i, elem := range arg.Elements {
+			elems[l-1-i] = elem
+		}
+		return &object.Array{Elements: elems}
+	case *object.String:
+		runes := []rune(arg.Value)
+		for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+			runes[i], runes[j] = runes[j], runes[i]
+		}
+		return &object.String{Value: string(runes)}
+	default:
+		return newError("argument to `reverse` not supported, got %s", arg.Type())
+	}
+}
+
+// builtinZip implements `zip(array1, array2, ...)`. The result is as long as the shortest array.
+func builtinZip(args ...object.Object) object.Object {
+	if len(args) == 0 {
+		return newError("wrong number of arguments. want at least 1, got=0")
+	}
+
+	arrs := make([]*object.Array, len(args))
+	length := -1
+	for i := range args {
+		arr, err := arrayArg("zip", args, i)
+		if err != nil {
+			return err
+		}
+		arrs[i] = arr
+		if l := len(arr.Elements); length < 0 || l < length {
+			length = l
+		}
+	}
+
+	tuples := make([]object.Object, length)
+	for i := range tuples {
+		tuple := make([]object.Object, len(arrs))
+		for j, arr := range arrs {
+			tuple[j] = arr.Elements[i]
+		}
+		tuples[i] = &object.Array{Elements: tuple}
+	}
+	return &object.Array{Elements: tuples}
+}
+
+// builtinFlatten implements `flatten(array, depth)`. Nested arrays are flattened recursively, or up
+// to `depth` levels if it is given.
+func builtinFlatten(args ...object.Object) object.Object {
+	if err := checkArgs(args, 1, 2); err != nil {
+		return err
+	}
+	arr, err := arrayArg("flatten", args, 0)
+	if err != nil {
+		return err
+	}
+
+	depth := int64(-1)
+	if len(args) == 2 {
+		d, ok := args[1].(*object.Integer)
+		if !ok || d.Value < 0 {
+			return newError("second argument to `flatten` must be non-negative Integer, got %s", args[1].Inspect())
+		}
+		depth = d.Value
+	}
+
+	return &object.Array{Elements: flatten(nil, arr.Elements, depth)}
+}
+
+func flatten(dst, elems []object.Object, depth int64) []object.Object {
+	for _, elem := range elems {
+		if arr, ok := elem.(*object.Array); ok && depth != 0 {
+			dst = flatten(dst, arr.Elements, depth-1)
+			continue
+		}
+		dst = append(dst, elem)
+	}
+	return dst
+}
+
+// builtinUniq returns a new array without duplicated elements, keeping the first occurrence of each.
+func builtinUniq(args ...object.Object) object.Object {
+	if err := checkArgs(args, 1, 1); err != nil {
+		return err
+	}
+	arr, err := arrayArg("uniq", args, 0)
+	if err != nil {
+		return err
+	}
+
+	// Hashable elements are looked up in a hash, and the others are compared one by one.
+	seen := object.NewHash(len(arr.Elements))
+	var unhashable []object.Object
+	elems := make([]object.Object, 0, len(arr.Elements))
+
+outer:
+	for _, elem := range arr.Elements {
+		if key, ok := elem.(object.Hashable); ok {
+			if _, dup := seen.Get(key); dup {
+				continue
+			}
+			seen.Set(key, TrueValue)
+		} else {
+			for _, u := range unhashable {
+				if object.Equal(elem, u) {
+					continue outer
+				}
+			}
+			unhashable = append(unhashable, elem)
+		}
+		elems = append(elems, elem)
+	}
+	return &object.Array{Elements: elems}
+}
+
+// testElements calls `fn` with each element of an array given to the builtin `name` until it
+// returns `stop` for an element, which is then returned. If no predicate is given, elements are
+// tested by their own truthiness.
+func testElements(name string, args []object.Object, stop bool) (object.Object, bool, *object.Error) {
+	if err := checkArgs(args, 1, 2); err != nil {
+		return nil, false, err
+	}
+	arr, err := arrayArg(name, args, 0)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var fn object.Object
+	if len(args) == 2 {
+		if fn, err = functionArg(name, args, 1); err != nil {
+			return nil, false, err
+		}
+	}
+
+	for _, elem := range arr.Elements {
+		result := elem
+		if fn != nil {
+			result = applyFunction(fn, []object.Object{elem})
+			if errObj, ok := result.(*object.Error); ok {
+				return nil, false, errObj
+			}
+		}
+		if isTruthy(result) == stop {
+			return elem, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func builtinAny(args ...object.Object) object.Object {
+	_, found, err := testElements("any", args, true)
+	if err != nil {
+		return err
+	}
+	return nativeBoolToBooleanObject(found)
+}
+
+func builtinAll(args ...object.Object) object.Object {
+	_, found, err := testElements("all", args, false)
+	if err != nil {
+		return err
+	}
+	return nativeBoolToBooleanObject(!found)
+}
+
+// builtinFind returns the first element for which the predicate is truthy, or nil.
+func builtinFind(args ...object.Object) object.Object {
+	if err := checkArgs(args, 2, 2); err != nil {
+		return err
+	}
+	elem, found, err := testElements("find", args, true)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return NilValue
+	}
+	return elem
+}
+
+// builtinRange implements `range(end)`, `range(start, end)` and `range(start, end, step)`, which
+// return an array of integers from start (0 by default) up to but not including end.
+func builtinRange(args ...object.Object) object.Object {
+	if err := checkArgs(args, 1, 3); err != nil {
+		return err
+	}
+
+	ints := make([]int64, len(args))
+	for i, arg := range args {
+		integer, ok := arg.(*object.Integer)
+		if !ok {
+			return newError("%s argument to `range` must be Integer, got %s", ordinal(i), arg.Type())
+		}
+		ints[i] = integer.Value
+	}
+
+	start, end, step := int64(0), ints[0], int64(1)
+	if len(ints) >= 2 {
+		start, end = ints[0], ints[1]
+	}
+	if len(ints) == 3 {
+		step = ints[2]
+	}
+	if step == 0 {
+		return newError("step of `range` must not be zero")
+	}
+
+	n := rangeLength(start, end, step)
+	if n > maxRangeLength {
+		return newError("range too large: %d elements", n)
+	}
+
+	// Count the elements rather than comparing with end, because i + step may overflow after the
+	// last element.
+	elems := make([]object.Object, 0, n)
+	for i, k := start, uint64(0); k < n; i, k = i+step, k+1 {
+		elems = append(elems, &object.Integer{Value: i})
+	}
+	return &object.Array{Elements: elems}
+}
+
+// maxRangeLength is the maximum number of elements of an array returned by `range`.
+const maxRangeLength = 1 << 26
+
+// rangeLength returns the number of integers from start up to but not including end by step. It
+// computes with unsigned integers, which hold the distance between any two int64 values.
+func rangeLength(start, end, step int64) uint64 {
+	switch {
+	case step > 0 && start < end:
+		return (uint64(end)-uint64(start)-1)/uint64(step) + 1
+	case step < 0 && start > end:
+		return (uint64(start)-uint64(end)-1)/(-uint64(step)) + 1
+	default:
+		return 0
+	}
+}
+
+// builtinEnumerate returns an array of [index, element] pairs.
+func builtinEnumerate(args ...object.Object) object.Object {
+	if err := checkArgs(args, 1, 1); err != nil {
+		return err
+	}
+	arr, err := arrayArg("enumerate", args, 0)
+	if err != nil {
+		return err
+	}
+
+	pairs := make([]object.Object, len(arr.Elements))
+	for i, elem := range arr.Elements {
+		pairs[i] = &object.Array{Elements: []object.Object{&object.Integer{Value: int64(i)}, elem}}
+	}
+	return &object.Array{Elements: pairs}
+}
@@ -0,0 +1,213 @@
+package eval
+
+import "github.com/skatsuta/monkey-interpreter/object"
+
+func init() {
+	for name, fn := range map[string]object.BuiltinFunction{
+		"keys":   builtinKeys,
+		"values": builtinValues,
+		"items":  builtinItems,
+		"has":    builtinHas,
+		"get":    builtinGet,
+		"set":    builtinSet,
+		"delete": builtinDelete,
+		"merge":  builtinMerge,
+	} {
+		builtins[name] = &object.Builtin{Fn: fn}
+	}
+}
+
+// hashArg returns the argument at `pos` as a hash, or an error mentioning the builtin `name`.
+func hashArg(name string, args []object.Object, pos int) (*object.Hash, *object.Error) {
+	hash, ok := args[pos].(*object.Hash)
+	if !ok {
+		return nil, newError("%s argument to `%s` must be Hash, got %s", ordinal(pos), name, args[pos].Type())
+	}
+	return hash, nil
+}
+
+// hashKeyArg returns the argument at `pos` if it can be used as a hash key.
+func hashKeyArg(args []object.Object, pos int) (object.Hashable, *object.Error) {
+	key, ok := args[pos].(object.Hashable)
+	if !ok {
+		return nil, newError("unusable as hash key: %s", args[pos].Type())
+	}
+	return key, nil
+}
+
+// mergeHashes returns a new hash with the pairs of all `hashes`. Values from later hashes win, and
+// keys keep the order of their first appearance.
+func mergeHashes(hashes ...*object.Hash) *object.Hash {
+	size := 0
+	for _, hash := range hashes {
+		size += hash.Len()
+	}
+
+	merged := object.NewHash(size)
+	for _, hash := range hashes {
+		for _, pair := range hash.Pairs() {
+			merged.Set(pair.Key.(object.Hashable), pair.Value)
+		}
+	}
+	return merged
+}
+
+func builtinKeys(args ...object.Object) object.Object {
+	if err := checkArgs(args, 1, 1); err != nil {
+		return err
+	}
+	hash, err := hashArg("keys", args, 0)
+	if err != nil {
+		return err
+	}
+
+	pairs := hash.Pairs()
+	keys := make([]object.Object, len(pairs))
+	for i, pair := range pairs {
+		keys[i] = pair.Key
+	}
+	return &object.Array{Elements: keys}
+}
+
+func builtinValues(args ...object.Object) object.Object {
+	if err := checkArgs(args, 1, 1); err != nil {
+		return err
+	}
+	hash, err := hashArg("values", args, 0)
+	if err != nil {
+		return err
+	}
+
+	pairs := hash.Pairs()
+	values := make([]object.Object, len(pairs))
+	for i, pair := range pairs {
+		values[i] = pair.Value
+	}
+	return &object.Array{Elements: values}
+}
+
+// builtinItems returns an array of [key, value] pairs.
+func builtinItems(args ...object.Object) object.Object {
+	if err := checkArgs(args, 1, 1); err != nil {
+		return err
+	}
+	hash, err := hashArg("items", args, 0)
+	if err != nil {
+		return err
+	}
+
+	pairs := hash.Pairs()
+	items := make([]object.Object, len(pairs))
+	for i, pair := range pairs {
+		items[i] = &object.Array{Elements: []object.Object{pair.Key, pair.Value}}
+	}
+	return &object.Array{Elements: items}
+}
+
+// builtinHas reports whether a hash has a key, even if the value stored for it is nil.
+func builtinHas(args ...object.Object) object.Object {
+	if err := checkArgs(args, 2, 2); err != nil {
+		return err
+	}
+	hash, err := hashArg("has", args, 0)
+	if err != nil {
+		return err
+	}
+	key, err := hashKeyArg(args, 1)
+	if err != nil {
+		return err
+	}
+
+	_, ok := hash.Get(key)
+	return nativeBoolToBooleanObject(ok)
+}
+
+// builtinGet implements `get(hash, key, default)`, which returns `default` (nil if omitted) when the
+// hash does not have the key.
+func builtinGet(args ...object.Object) object.Object {
+	if err := checkArgs(args, 2, 3); err != nil {
+		return err
+	}
+	hash, err := hashArg("get", args, 0)
+	if err != nil {
+		return err
+	}
+	key, err := hashKeyArg(args, 1)
+	if err != nil {
+		return err
+	}
+
+	if value, ok := hash.Get(key); ok {
+		return value
+	}
+	if len(args) == 3 {
+		return args[2]
+	}
+	return NilValue
+}
+
+// builtinSet returns a copy of a hash with the value for a key set.
+func builtinSet(args ...object.Object) object.Object {
+	if err := checkArgs(args, 3, 3); err != nil {
+		return err
+	}
+	hash, err := hashArg("set", args, 0)
+	if err != nil {
+		return err
+	}
+	key, err := hashKeyArg(args, 1)
+	if err != nil {
+		return err
+	}
+
+	copied := mergeHashes(hash)
+	copied.Set(key, args[2])
+	return copied
+}
+
+// builtinDelete returns a copy of a hash without the given keys.
+func builtinDelete(args ...object.Object) object.Object {
+	if len(args) < 2 {
+		return newError("wrong number of arguments. want at least 2, got=%d", len(args))
+	}
+	hash, err := hashArg("delete", args, 0)
+	if err != nil {
+		return err
+	}
+
+	deleted := object.NewHash(0)
+	for i := 1; i < len(args); i++ {
+		key, err := hashKeyArg(args, i)
+		if err != nil {
+			return err
+		}
+		deleted.Set(key, TrueValue)
+	}
+
+	pairs := hash.Pairs()
+	result := object.NewHash(len(pairs))
+	for _, pair := range pairs {
+		key := pair.Key.(object.Hashable)
+		if _, ok := deleted.Get(key); !ok {
+			result.Set(key, pair.Value)
+		}
+	}
+	return result
+}
+
+// builtinMerge implements `merge(hash1, hash2, ...)` like chained `+` operators on hashes.
+func builtinMerge(args ...object.Object) object.Object {
+	if len(args) == 0 {
+		return newError("wrong number of arguments. want at least 1, got=0")
+	}
+
+	hashes := make([]*object.Hash, len(args))
+	for i := range args {
+		hash, err := hashArg("merge", args, i)
+		if err != nil {
+			return err
+		}
+		hashes[i] = hash
+	}
+	return mergeHashes(hashes...)
+}
@@ -0,0 +1,228 @@
+package eval
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/skatsuta/monkey-interpreter/object"
+)
+
+func init() {
+	builtins["json_parse"] = &object.Builtin{Fn: builtinJSONParse}
+	builtins["json_stringify"] = &object.Builtin{Fn: builtinJSONStringify}
+}
+
+// builtinJSONParse converts JSON text into Monkey objects. Objects become hashes keeping the order of
+// keys, and numbers become integers if they are integral and fit into an Integer.
+func builtinJSONParse(args ...object.Object) object.Object {
+	if err := checkArgs(args, 1, 1); err != nil {
+		return err
+	}
+	str, err := stringArg("json_parse", args, 0)
+	if err != nil {
+		return err
+	}
+
+	// Check the syntax first, since Unmarshal reports errors more precisely than Decoder.Token.
+	var raw json.RawMessage
+	if err := json.Unmarshal([]byte(str), &raw); err != nil {
+		return newError("could not parse JSON: %s", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	obj, parseErr := parseJSONValue(dec)
+	if parseErr != nil {
+		return newError("could not parse JSON: %s", parseErr)
+	}
+	return obj
+}
+
+func parseJSONValue(dec *json.Decoder) (object.Object, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok := tok.(type) {
+	case nil:
+		return NilValue, nil
+	case bool:
+		return nativeBoolToBooleanObject(tok), nil
+	case string:
+		return &object.String{Value: tok}, nil
+	case json.Number:
+		if i, err := strconv.ParseInt(tok.String(), 10, 64); err == nil {
+			return &object.Integer{Value: i}, nil
+		}
+		f, err := tok.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("number %s out of range", tok)
+		}
+		return &object.Float{Value: f}, nil
+	case json.Delim:
+		if tok == '[' {
+			return parseJSONArray(dec)
+		}
+		return parseJSONObject(dec)
+	default:
+		return nil, fmt.Errorf("unexpected token %v", tok)
+	}
+}
+
+func parseJSONArray(dec *json.Decoder) (object.Object, error) {
+	elems := []object.Object{}
+	for dec.More() {
+		elem, err := parseJSONValue(dec)
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, elem)
+	}
+
+	// Consume the closing bracket.
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return &object.Array{Elements: elems}, nil
+}
+
+func parseJSONObject(dec *json.Decoder) (object.Object, error) {
+	hash := object.NewHash(0)
+	for dec.More() {
+		// The decoder guarantees that keys are strings.
+		key, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		value, err := parseJSONValue(dec)
+		if err != nil {
+			return nil, err
+		}
+		hash.Set(&object.String{Value: key.(string)}, value)
+	}
+
+	// Consume the closing brace.
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return hash, nil
+}
+
+// maxJSONIndent is the maximum length of the indent of JSON text, in spaces or in characters of an
+// indent string. JSON.stringify of JavaScript has the same limit, though it cuts longer indents
+// instead of rejecting them.
+const maxJSONIndent = 10
+
+// builtinJSONStringify implements `json_stringify(obj, indent)`, which converts an object into
+// compact JSON text, or into indented text if `indent` is given as a number of spaces or a string.
+// Only nil, booleans, numbers except NaN and Inf, strings, arrays and hashes with string keys can be
+// converted.
+func builtinJSONStringify(args ...object.Object) object.Object {
+	if err := checkArgs(args, 1, 2); err != nil {
+		return err
+	}
+
+	var indent string
+	if len(args) == 2 {
+		switch arg := args[1].(type) {
+		case *object.Integer:
+			if arg.Value < 0 {
+				return newError("indent of `json_stringify` must not be negative, got %d", arg.Value)
+			}
+			if arg.Value > maxJSONIndent {
+				return newError("indent of `json_stringify` must be at most %d, got %d", maxJSONIndent, arg.Value)
+			}
+			indent = strings.Repeat(" ", int(arg.Value))
+		case *object.String:
+			if l := utf8.RuneCountInString(arg.Value); l > maxJSONIndent {
+				return newError("indent of `json_stringify` must be at most %d characters, got %d", maxJSONIndent, l)
+			}
+			indent = arg.Value
+		default:
+			return newError("second argument to `json_stringify` must be Integer or String, got %s", arg.Type())
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, args[0]); err != nil {
+		return err
+	}
+
+	if indent == "" {
+		return &object.String{Value: buf.String()}
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, buf.Bytes(), "", indent); err != nil {
+		return newError("could not indent JSON: %s", err)
+	}
+	return &object.String{Value: indented.String()}
+}
+
+func writeJSON(buf *bytes.Buffer, obj object.Object) *object.Error {
+	switch obj := obj.(type) {
+	case *object.Nil:
+		buf.WriteString("null")
+	case *object.Boolean:
+		buf.WriteString(strconv.FormatBool(obj.Value))
+	case *object.Integer:
+		buf.WriteString(strconv.FormatInt(obj.Value, 10))
+	case *object.Float:
+		if math.IsNaN(obj.Value) || math.IsInf(obj.Value, 0) {
+			return newError("cannot convert %s to JSON", obj.Inspect())
+		}
+		// Keep a fraction in integral floats so that they are parsed back as floats.
+		s := strconv.FormatFloat(obj.Value, 'g', -1, 64)
+		if !strings.ContainsAny(s, ".e") {
+			s += ".0"
+		}
+		buf.WriteString(s)
+	case *object.String:
+		writeJSONString(buf, obj.Value)
+	case *object.Array:
+		buf.WriteByte('[')
+		for i, elem := range obj.Elements {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSON(buf, elem); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case *object.Hash:
+		buf.WriteByte('{')
+		for i, pair := range obj.Pairs() {
+			key, ok := pair.Key.(*object.String)
+			if !ok {
+				return newError("cannot convert hash key of %s to JSON", pair.Key.Type())
+			}
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeJSONString(buf, key.Value)
+			buf.WriteByte(':')
+			if err := writeJSON(buf, pair.Value); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		return newError("cannot convert %s to JSON", obj.Type())
+	}
+	return nil
+}
+
+func writeJSONString(buf *bytes.Buffer, s string) {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	// Encoding a string never fails.
+	_ = enc.Encode(s)
+	// Remove the newline added by Encode.
+	buf.Truncate(buf.Len() - 1)
+}
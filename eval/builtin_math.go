@@ -0,0 +1,308 @@
+package eval
+
+import (
+	"math"
+
+	"github.com/skatsuta/monkey-interpreter/object"
+)
+
+// constants are predefined values which can be shadowed by variables like builtins, unless
+// builtins are strict.
+var constants = map[string]object.Object{
+	"PI":  &object.Float{Value: math.Pi},
+	"E":   &object.Float{Value: math.E},
+	"INF": &object.Float{Value: math.Inf(1)},
+	"NAN": &object.Float{Value: math.NaN()},
+}
+
+func init() {
+	for name, fn := range map[string]object.BuiltinFunction{
+		"abs":    builtinAbs,
+		"floor":  roundingFunc("floor", math.Floor),
+		"ceil":   roundingFunc("ceil", math.Ceil),
+		"round":  builtinRound,
+		"min":    extremumFunc("min", -1),
+		"max":    extremumFunc("max", 1),
+		"pow":    builtinPow,
+		"sqrt":   floatFunc("sqrt", math.Sqrt),
+		"exp":    floatFunc("exp", math.Exp),
+		"log":    floatFunc("log", math.Log),
+		"log2":   floatFunc("log2", math.Log2),
+		"log10":  floatFunc("log10", math.Log10),
+		"sin":    floatFunc("sin", math.Sin),
+		"cos":    floatFunc("cos", math.Cos),
+		"tan":    floatFunc("tan", math.Tan),
+		"asin":   floatFunc("asin", math.Asin),
+		"acos":   floatFunc("acos", math.Acos),
+		"atan":   floatFunc("atan", math.Atan),
+		"atan2":  builtinAtan2,
+		"is_nan": builtinIsNaN,
+		"is_inf": builtinIsInf,
+	} {
+		builtins[name] = &object.Builtin{Fn: fn}
+	}
+}
+
+// numberArg returns the argument at `pos` as a float64, or an error mentioning the builtin `name`.
+func numberArg(name string, args []object.Object, pos int) (float64, *object.Error) {
+	switch arg := args[pos].(type) {
+	case *object.Integer:
+		return float64(arg.Value), nil
+	case *object.Float:
+		return arg.Value, nil
+	default:
+		return 0, newError("%s argument to `%s` must be Integer or Float, got %s", ordinal(pos), name, arg.Type())
+	}
+}
+
+// floatFunc returns a builtin function which applies `fn` to a number and always returns a Float.
+func floatFunc(name string, fn func(float64) float64) object.BuiltinFunction {
+	return func(args ...object.Object) object.Object {
+		if err := checkArgs(args, 1, 1); err != nil {
+			return err
+		}
+		x, err := numberArg(name, args, 0)
+		if err != nil {
+			return err
+		}
+		return &object.Float{Value: fn(x)}
+	}
+}
+
+// roundingFunc returns a builtin function which returns an Integer as it is and applies `fn` to a
+// Float.
+func roundingFunc(name string, fn func(float64) float64) object.BuiltinFunction {
+	return func(args ...object.Object) object.Object {
+		if err := checkArgs(args, 1, 1); err != nil {
+			return err
+		}
+
+		switch arg := args[0].(type) {
+		case *object.Integer:
+			return arg
+		case *object.Float:
+			return &object.Float{Value: fn(arg.Value)}
+		default:
+			return newError("argument to `%s` must be Integer or Float, got %s", name, arg.Type())
+		}
+	}
+}
+
+func builtinAbs(args ...object.Object) object.Object {
+	if err := checkArgs(args, 1, 1); err != nil {
+		return err
+	}
+
+	switch arg := args[0].(type) {
+	case *object.Integer:
+		if arg.Value < 0 {
+			return &object.Integer{Value: -arg.Value}
+		}
+		return arg
+	case *object.Float:
+		return &object.Float{Value: math.Abs(arg.Value)}
+	default:
+		return newError("argument to `abs` must be Integer or Float, got %s", arg.Type())
+	}
+}
+
+// builtinRound implements `round(x, digits)`, which rounds half away from zero to the given number
+// of digits after the decimal point, 0 by default. Negative digits round to tens, hundreds and so on.
+func builtinRound(args ...object.Object) object.Object {
+	if err := checkArgs(args, 1, 2); err != nil {
+		return err
+	}
+	if len(args) == 1 {
+		return roundingFunc("round", math.Round)(args...)
+	}
+
+	x, err := numberArg("round", args, 0)
+	if err != nil {
+		return err
+	}
+	digits, err := integerArg("round", args, 1)
+	if err != nil {
+		return err
+	}
+	if integer, ok := args[0].(*object.Integer); ok {
+		return roundInteger(integer, digits)
+	}
+
+	var rounded float64
+	switch scale := math.Pow(10, float64(digits)); {
+	case math.IsInf(x, 0) || math.IsInf(x*scale, 0):
+		// x has fewer digits than wanted, or is infinite itself.
+		rounded = x
+	case scale == 0:
+		// Rounding to a power of ten beyond the range of Float leaves nothing of x.
+		rounded = 0
+	default:
+		rounded = math.Round(x*scale) / scale
+	}
+	return &object.Float{Value: rounded}
+}
+
+// roundInteger rounds an Integer half away from zero to the given number of digits in integer
+// arithmetic, since a Float cannot hold every Integer. As with `pow`, the result is a Float if it
+// overflows Integer.
+func roundInteger(integer *object.Integer, digits int64) object.Object {
+	if digits >= 0 {
+		return integer
+	}
+	x := integer.Value
+
+	unit, ok := int64(1), true
+	for d := digits; d < 0 && ok; d++ {
+		unit, ok = multiplyInt64(unit, 10)
+	}
+	if !ok {
+		// The unit is at least 10^19, which exceeds Integer. x is less than half of any larger unit,
+		// so that it rounds to 0 unless it is at least half of 10^19.
+		if digits == -19 && (x >= 5e18 || x <= -5e18) {
+			return &object.Float{Value: math.Copysign(1e19, float64(x))}
+		}
+		return &object.Integer{Value: 0}
+	}
+
+	q, r := x/unit, x%unit
+	if r < 0 {
+		r = -r
+	}
+	if r >= unit-r {
+		if x < 0 {
+			q--
+		} else {
+			q++
+		}
+	}
+
+	rounded, ok := multiplyInt64(q, unit)
+	if !ok {
+		return &object.Float{Value: float64(q) * float64(unit)}
+	}
+	return &object.Integer{Value: rounded}
+}
+
+// extremumFunc returns a builtin function which returns the minimum (sign < 0) or maximum
+// (sign > 0) of its arguments, or of the elements of a single array argument. As with arithmetic
+// operators, the result is a Float if any of the numbers is a Float, and NaN if any of them is NaN.
+func extremumFunc(name string, sign int) object.BuiltinFunction {
+	return func(args ...object.Object) object.Object {
+		if len(args) == 1 {
+			if arr, ok := args[0].(*object.Array); ok {
+				args = arr.Elements
+			}
+		}
+		if len(args) == 0 {
+			return newError("`%s` needs at least 1 number", name)
+		}
+
+		var result object.Object
+		isFloat := false
+		for _, arg := range args {
+			switch arg := arg.(type) {
+			case *object.Integer:
+			case *object.Float:
+				if math.IsNaN(arg.Value) {
+					return arg
+				}
+				isFloat = true
+			default:
+				return newError("arguments to `%s` must be Integer or Float, got %s", name, arg.Type())
+			}
+
+			if result == nil {
+				result = arg
+				continue
+			}
+			// Compare never fails here because both are numbers other than NaN.
+			if c, _ := object.Compare(arg, result); c*sign > 0 {
+				result = arg
+			}
+		}
+
+		if integer, ok := result.(*object.Integer); ok && isFloat {
+			return &object.Float{Value: float64(integer.Value)}
+		}
+		return result
+	}
+}
+
+// builtinPow implements `pow(x, y)`. An Integer raised to a non-negative Integer is an Integer
+// unless it overflows, and the result is a Float otherwise.
+func builtinPow(args ...object.Object) object.Object {
+	if err := checkArgs(args, 2, 2); err != nil {
+		return err
+	}
+
+	base, baseIsInt := args[0].(*object.Integer)
+	exp, expIsInt := args[1].(*object.Integer)
+	if baseIsInt && expIsInt && exp.Value >= 0 {
+		result, b, ok := int64(1), base.Value, true
+		for e := exp.Value; e > 0 && ok; e >>= 1 {
+			if e&1 == 1 {
+				result, ok = multiplyInt64(result, b)
+			}
+			if e > 1 && ok {
+				b, ok = multiplyInt64(b, b)
+			}
+		}
+		if ok {
+			return &object.Integer{Value: result}
+		}
+	}
+
+	x, err := numberArg("pow", args, 0)
+	if err != nil {
+		return err
+	}
+	y, err := numberArg("pow", args, 1)
+	if err != nil {
+		return err
+	}
+	return &object.Float{Value: math.Pow(x, y)}
+}
+
+// multiplyInt64 returns a * b, and whether it is free of overflow.
+func multiplyInt64(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	if (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, false
+	}
+	c := a * b
+	return c, c/b == a
+}
+
+func builtinAtan2(args ...object.Object) object.Object {
+	if err := checkArgs(args, 2, 2); err != nil {
+		return err
+	}
+	y, err := numberArg("atan2", args, 0)
+	if err != nil {
+		return err
+	}
+	x, err := numberArg("atan2", args, 1)
+	if err != nil {
+		return err
+	}
+	return &object.Float{Value: math.Atan2(y, x)}
+}
+
+func builtinIsNaN(args ...object.Object) object.Object {
+	if err := checkArgs(args, 1, 1); err != nil {
+		return err
+	}
+	f, ok := args[0].(*object.Float)
+	return nativeBoolToBooleanObject(ok && math.IsNaN(f.Value))
+}
+
+// builtinIsInf reports whether its argument is positive or negative infinity.
+func builtinIsInf(args ...object.Object) object.Object {
+	if err := checkArgs(args, 1, 1); err != nil {
+		return err
+	}
+	f, ok := args[0].(*object.Float)
+	return nativeBoolToBooleanObject(ok && math.IsInf(f.Value, 0))
+}
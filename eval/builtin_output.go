@@ -0,0 +1,195 @@
+package eval
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/skatsuta/monkey-interpreter/object"
+)
+
+func init() {
+	envBuiltins["puts"] = builtinPuts
+	envBuiltins["print"] = builtinPrint
+	envBuiltins["printf"] = builtinPrintf
+	builtins["sprintf"] = &object.Builtin{Fn: builtinSprintf}
+}
+
+// builtinPuts writes each argument on its own line to the output of `env`.
+func builtinPuts(env object.Environment) object.BuiltinFunction {
+	w := env.Output()
+	return func(args ...object.Object) object.Object {
+		for _, arg := range args {
+			if _, err := fmt.Fprintln(w, arg.Inspect()); err != nil {
+				return newError("could not write output: %s", err)
+			}
+		}
+		return NilValue
+	}
+}
+
+// builtinPrint writes the arguments separated by spaces, without a trailing newline.
+func builtinPrint(env object.Environment) object.BuiltinFunction {
+	w := env.Output()
+	return func(args ...object.Object) object.Object {
+		strs := make([]string, len(args))
+		for i, arg := range args {
+			strs[i] = arg.Inspect()
+		}
+
+		if _, err := io.WriteString(w, strings.Join(strs, " ")); err != nil {
+			return newError("could not write output: %s", err)
+		}
+		return NilValue
+	}
+}
+
+// builtinPrintf writes the arguments formatted like `sprintf`, without a trailing newline.
+func builtinPrintf(env object.Environment) object.BuiltinFunction {
+	w := env.Output()
+	return func(args ...object.Object) object.Object {
+		formatted := builtinSprintf(args...)
+		if isError(formatted) {
+			return formatted
+		}
+
+		if _, err := io.WriteString(w, formatted.(*object.String).Value); err != nil {
+			return newError("could not write output: %s", err)
+		}
+		return NilValue
+	}
+}
+
+func builtinSprintf(args ...object.Object) object.Object {
+	if len(args) == 0 {
+		return newError("wrong number of arguments. want at least 1, got=0")
+	}
+	format, err := stringArg("sprintf", args, 0)
+	if err != nil {
+		return err
+	}
+
+	str, err := sprintf(format, args[1:])
+	if err != nil {
+		return err
+	}
+	return &object.String{Value: str}
+}
+
+// sprintf formats `args` according to `format`. A verb is introduced by '%', followed by optional
+// flags ('-', '+' and '0'), width and precision, and one of the verbs below. "%%" is a literal '%'.
+//
+//	%d	Integer in decimal
+//	%f	Integer or Float in decimal with a fraction, 6 digits after the point by default
+//	%s	String as it is
+//	%q	String quoted with double quotes and escaped
+//	%v	any value as it is printed by `puts`
+//
+// Width and precision count Unicode characters, and may be at most maxFormatNumber. Precision
+// limits the number of characters for %s, %q and %v.
+func sprintf(format string, args []object.Object) (string, *object.Error) {
+	var buf strings.Builder
+	argIndex := 0
+
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		if c != '%' {
+			buf.WriteByte(c)
+			continue
+		}
+
+		// Parse flags, width and precision into a Go format spec.
+		start := i
+		i++
+		for i < len(format) && strings.IndexByte("-+0", format[i]) >= 0 {
+			i++
+		}
+		widthStart := i
+		for i < len(format) && '0' <= format[i] && format[i] <= '9' {
+			i++
+		}
+		width := format[widthStart:i]
+		var precision string
+		if i < len(format) && format[i] == '.' {
+			i++
+			precisionStart := i
+			for i < len(format) && '0' <= format[i] && format[i] <= '9' {
+				i++
+			}
+			precision = format[precisionStart:i]
+		}
+		if i >= len(format) {
+			return "", newError("incomplete format verb %s at end of format", format[start:])
+		}
+
+		verb, size := utf8.DecodeRuneInString(format[i:])
+		i += size - 1
+		spec := format[start:i] // without the verb
+
+		if !formatNumberFits(width) || !formatNumberFits(precision) {
+			return "", newError("width or precision of format verb %s%c too large", spec, verb)
+		}
+
+		if verb == '%' {
+			if spec != "%" {
+				return "", newError("unknown format verb %s%%", spec)
+			}
+			buf.WriteByte('%')
+			continue
+		}
+
+		if argIndex >= len(args) {
+			return "", newError("missing argument for format verb %s%c", spec, verb)
+		}
+		arg := args[argIndex]
+		argIndex++
+
+		switch verb {
+		case 'd':
+			integer, ok := arg.(*object.Integer)
+			if !ok {
+				return "", newError("format verb %s%c needs Integer, got %s", spec, verb, arg.Type())
+			}
+			fmt.Fprintf(&buf, spec+"d", integer.Value)
+		case 'f':
+			switch num := arg.(type) {
+			case *object.Integer:
+				fmt.Fprintf(&buf, spec+"f", float64(num.Value))
+			case *object.Float:
+				fmt.Fprintf(&buf, spec+"f", num.Value)
+			default:
+				return "", newError("format verb %s%c needs Integer or Float, got %s", spec, verb, arg.Type())
+			}
+		case 's', 'q':
+			str, ok := arg.(*object.String)
+			if !ok {
+				return "", newError("format verb %s%c needs String, got %s", spec, verb, arg.Type())
+			}
+			fmt.Fprintf(&buf, spec+string(verb), str.Value)
+		case 'v':
+			fmt.Fprintf(&buf, spec+"s", arg.Inspect())
+		default:
+			return "", newError("unknown format verb %s%c", spec, verb)
+		}
+	}
+
+	if argIndex < len(args) {
+		return "", newError("too many arguments for format. want=%d, got=%d", argIndex, len(args))
+	}
+	return buf.String(), nil
+}
+
+// maxFormatNumber is the maximum width and precision of a format verb.
+const maxFormatNumber = 10000
+
+// formatNumberFits reports whether `digits`, the width or precision of a format verb, is at most
+// maxFormatNumber. Empty digits mean no width or precision.
+func formatNumberFits(digits string) bool {
+	if digits == "" {
+		return true
+	}
+	n, err := strconv.Atoi(digits)
+	return err == nil && n <= maxFormatNumber
+}
@@ -0,0 +1,341 @@
+package eval
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/skatsuta/monkey-interpreter/object"
+)
+
+func init() {
+	for name, fn := range map[string]object.BuiltinFunction{
+		"split":       builtinSplit,
+		"join":        builtinJoin,
+		"trim":        builtinTrim,
+		"upper":       builtinUpper,
+		"lower":       builtinLower,
+		"replace":     builtinReplace,
+		"contains":    builtinContains,
+		"starts_with": builtinStartsWith,
+		"ends_with":   builtinEndsWith,
+		"index_of":    builtinIndexOf,
+		"repeat":      builtinRepeat,
+		"pad_left":    builtinPadLeft,
+		"pad_right":   builtinPadRight,
+		"chars":       builtinChars,
+		"lines":       builtinLines,
+	} {
+		builtins[name] = &object.Builtin{Fn: fn}
+	}
+}
+
+// stringArg returns the argument at `pos` as a Go string, or an error mentioning the builtin `name`.
+func stringArg(name string, args []object.Object, pos int) (string, *object.Error) {
+	str, ok := args[pos].(*object.String)
+	if !ok {
+		return "", newError("%s argument to `%s` must be String, got %s", ordinal(pos), name, args[pos].Type())
+	}
+	return str.Value, nil
+}
+
+// integerArg returns the argument at `pos` as an int64, or an error mentioning the builtin `name`.
+func integerArg(name string, args []object.Object, pos int) (int64, *object.Error) {
+	integer, ok := args[pos].(*object.Integer)
+	if !ok {
+		return 0, newError("%s argument to `%s` must be Integer, got %s", ordinal(pos), name, args[pos].Type())
+	}
+	return integer.Value, nil
+}
+
+// stringArgs checks that `args` are `n` strings and returns their values.
+func stringArgs(name string, args []object.Object, n int) ([]string, *object.Error) {
+	if err := checkArgs(args, n, n); err != nil {
+		return nil, err
+	}
+
+	strs := make([]string, n)
+	for i := range args {
+		str, err := stringArg(name, args, i)
+		if err != nil {
+			return nil, err
+		}
+		strs[i] = str
+	}
+	return strs, nil
+}
+
+func newStringArray(strs []string) *object.Array {
+	elems := make([]object.Object, len(strs))
+	for i, str := range strs {
+		elems[i] = &object.String{Value: str}
+	}
+	return &object.Array{Elements: elems}
+}
+
+// builtinSplit implements `split(str, sep)`. Without a separator, the string is split around runs of
+// white space, and an empty separator splits it into characters.
+func builtinSplit(args ...object.Object) object.Object {
+	if err := checkArgs(args, 1, 2); err != nil {
+		return err
+	}
+	str, err := stringArg("split", args, 0)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 1 {
+		return newStringArray(strings.Fields(str))
+	}
+	sep, err := stringArg("split", args, 1)
+	if err != nil {
+		return err
+	}
+	return newStringArray(strings.Split(str, sep))
+}
+
+// builtinJoin implements `join(array, sep)`, which concatenates an array of strings with an
+// optional separator between them.
+func builtinJoin(args ...object.Object) object.Object {
+	if err := checkArgs(args, 1, 2); err != nil {
+		return err
+	}
+	arr, err := arrayArg("join", args, 0)
+	if err != nil {
+		return err
+	}
+
+	var sep string
+	if len(args) == 2 {
+		if sep, err = stringArg("join", args, 1); err != nil {
+			return err
+		}
+	}
+
+	strs := make([]string, len(arr.Elements))
+	for i, elem := range arr.Elements {
+		str, ok := elem.(*object.String)
+		if !ok {
+			return newError("elements of array passed to `join` must be String, got %s", elem.Type())
+		}
+		strs[i] = str.Value
+	}
+	return &object.String{Value: strings.Join(strs, sep)}
+}
+
+// builtinTrim implements `trim(str, cutset)`, which removes leading and trailing white space, or
+// characters contained in the optional cutset.
+func builtinTrim(args ...object.Object) object.Object {
+	if err := checkArgs(args, 1, 2); err != nil {
+		return err
+	}
+	str, err := stringArg("trim", args, 0)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 1 {
+		return &object.String{Value: strings.TrimSpace(str)}
+	}
+	cutset, err := stringArg("trim", args, 1)
+	if err != nil {
+		return err
+	}
+	return &object.String{Value: strings.Trim(str, cutset)}
+}
+
+func builtinUpper(args ...object.Object) object.Object {
+	strs, err := stringArgs("upper", args, 1)
+	if err != nil {
+		return err
+	}
+	return &object.String{Value: strings.ToUpper(strs[0])}
+}
+
+func builtinLower(args ...object.Object) object.Object {
+	strs, err := stringArgs("lower", args, 1)
+	if err != nil {
+		return err
+	}
+	return &object.String{Value: strings.ToLower(strs[0])}
+}
+
+// builtinReplace implements `replace(str, old, new, n)`, which replaces the first `n` occurrences of
+// `old`, or all of them if `n` is omitted.
+func builtinReplace(args ...object.Object) object.Object {
+	if err := checkArgs(args, 3, 4); err != nil {
+		return err
+	}
+	strs, err := stringArgs("replace", args[:3], 3)
+	if err != nil {
+		return err
+	}
+
+	n := int64(-1)
+	if len(args) == 4 {
+		if n, err = integerArg("replace", args, 3); err != nil {
+			return err
+		}
+	}
+
+	// An empty `old` matches between all the characters, so that the result may grow quadratically.
+	count := int64(strings.Count(strs[0], strs[1]))
+	if n >= 0 && n < count {
+		count = n
+	}
+	if growth := len(strs[2]) - len(strs[1]); growth > 0 {
+		if !repeatFits(growth, count) || int64(len(strs[0]))+count*int64(growth) > maxRepeatLength {
+			return newError("result of `replace` too large")
+		}
+	}
+	return &object.String{Value: strings.Replace(strs[0], strs[1], strs[2], int(n))}
+}
+
+func builtinContains(args ...object.Object) object.Object {
+	strs, err := stringArgs("contains", args, 2)
+	if err != nil {
+		return err
+	}
+	return nativeBoolToBooleanObject(strings.Contains(strs[0], strs[1]))
+}
+
+func builtinStartsWith(args ...object.Object) object.Object {
+	strs, err := stringArgs("starts_with", args, 2)
+	if err != nil {
+		return err
+	}
+	return nativeBoolToBooleanObject(strings.HasPrefix(strs[0], strs[1]))
+}
+
+func builtinEndsWith(args ...object.Object) object.Object {
+	strs, err := stringArgs("ends_with", args, 2)
+	if err != nil {
+		return err
+	}
+	return nativeBoolToBooleanObject(strings.HasSuffix(strs[0], strs[1]))
+}
+
+// builtinIndexOf returns the character index of the first occurrence of a substring, or -1 if it is
+// not present. The index can be used with the index and slice operators on strings.
+func builtinIndexOf(args ...object.Object) object.Object {
+	strs, err := stringArgs("index_of", args, 2)
+	if err != nil {
+		return err
+	}
+
+	i := strings.Index(strs[0], strs[1])
+	if i > 0 {
+		i = utf8.RuneCountInString(strs[0][:i])
+	}
+	return &object.Integer{Value: int64(i)}
+}
+
+func builtinRepeat(args ...object.Object) object.Object {
+	if err := checkArgs(args, 2, 2); err != nil {
+		return err
+	}
+	str, err := stringArg("repeat", args, 0)
+	if err != nil {
+		return err
+	}
+	n, err := integerArg("repeat", args, 1)
+	if err != nil {
+		return err
+	}
+
+	if n < 0 {
+		return newError("negative repeat count: %d", n)
+	}
+	if !repeatFits(len(str), n) {
+		return newError("repeat count too large: %d", n)
+	}
+	return &object.String{Value: strings.Repeat(str, int(n))}
+}
+
+func builtinPadLeft(args ...object.Object) object.Object {
+	return pad("pad_left", args, true)
+}
+
+func builtinPadRight(args ...object.Object) object.Object {
+	return pad("pad_right", args, false)
+}
+
+// pad implements `pad_left(str, width, pad)` and `pad_right(str, width, pad)`, which fill a string
+// up to `width` characters with the optional `pad` string, a space by default. The fill is cut off
+// if `pad` does not fit evenly.
+func pad(name string, args []object.Object, left bool) object.Object {
+	if err := checkArgs(args, 2, 3); err != nil {
+		return err
+	}
+	str, err := stringArg(name, args, 0)
+	if err != nil {
+		return err
+	}
+	width, err := integerArg(name, args, 1)
+	if err != nil {
+		return err
+	}
+
+	padding := " "
+	if len(args) == 3 {
+		if padding, err = stringArg(name, args, 2); err != nil {
+			return err
+		}
+		if padding == "" {
+			return newError("third argument to `%s` must not be empty", name)
+		}
+	}
+
+	if !repeatFits(1, width) {
+		return newError("width of `%s` too large: %d", name, width)
+	}
+
+	n := int(width) - utf8.RuneCountInString(str)
+	if n <= 0 {
+		return &object.String{Value: str}
+	}
+
+	padRunes := []rune(padding)
+	fill := make([]rune, n)
+	for i := range fill {
+		fill[i] = padRunes[i%len(padRunes)]
+	}
+
+	if left {
+		return &object.String{Value: string(fill) + str}
+	}
+	return &object.String{Value: str + string(fill)}
+}
+
+// builtinChars returns an array of the characters of a string.
+func builtinChars(args ...object.Object) object.Object {
+	strs, err := stringArgs("chars", args, 1)
+	if err != nil {
+		return err
+	}
+
+	chars := make([]object.Object, 0, utf8.RuneCountInString(strs[0]))
+	for _, r := range strs[0] {
+		chars = append(chars, &object.String{Value: string(r)})
+	}
+	return &object.Array{Elements: chars}
+}
+
+// builtinLines splits a string into lines. Both "\n" and "\r\n" end a line, and a final line ending
+// does not start a new empty line.
+func builtinLines(args ...object.Object) object.Object {
+	strs, err := stringArgs("lines", args, 1)
+	if err != nil {
+		return err
+	}
+
+	str := strings.TrimSuffix(strs[0], "\n")
+	if strs[0] == "" {
+		return newStringArray([]string{})
+	}
+
+	lines := strings.Split(str, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return newStringArray(lines)
+}
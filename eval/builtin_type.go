@@ -0,0 +1,161 @@
+package eval
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+
+	"github.com/skatsuta/monkey-interpreter/object"
+)
+
+func init() {
+	for name, fn := range map[string]object.BuiltinFunction{
+		"type":  builtinType,
+		"int":   builtinInt,
+		"float": builtinFloat,
+		"str":   builtinStr,
+		"bool":  builtinBool,
+
+		"is_int":      typePredicate("is_int", object.IntegerType),
+		"is_float":    typePredicate("is_float", object.FloatType),
+		"is_number":   typePredicate("is_number", object.IntegerType, object.FloatType),
+		"is_string":   typePredicate("is_string", object.StringType),
+		"is_bool":     typePredicate("is_bool", object.BooleanType),
+		"is_nil":      typePredicate("is_nil", object.NilType),
+		"is_array":    typePredicate("is_array", object.ArrayType),
+		"is_hash":     typePredicate("is_hash", object.HashType),
+		"is_function": typePredicate("is_function", object.FunctionType, object.BuiltinType),
+	} {
+		builtins[name] = &object.Builtin{Fn: fn}
+	}
+}
+
+// builtinType returns the name of the type of its argument, e.g. "Integer".
+func builtinType(args ...object.Object) object.Object {
+	if err := checkArgs(args, 1, 1); err != nil {
+		return err
+	}
+	return &object.String{Value: string(args[0].Type())}
+}
+
+// typePredicate returns a builtin function which reports whether its argument has any of `types`.
+func typePredicate(name string, types ...object.Type) object.BuiltinFunction {
+	return func(args ...object.Object) object.Object {
+		if err := checkArgs(args, 1, 1); err != nil {
+			return err
+		}
+
+		typ := args[0].Type()
+		for _, t := range types {
+			if typ == t {
+				return TrueValue
+			}
+		}
+		return FalseValue
+	}
+}
+
+// builtinInt converts its argument to an Integer. Floats are truncated toward zero, and strings must
+// hold a decimal integer with an optional sign and nothing else, not even spaces.
+func builtinInt(args ...object.Object) object.Object {
+	if err := checkArgs(args, 1, 1); err != nil {
+		return err
+	}
+
+	switch arg := args[0].(type) {
+	case *object.Integer:
+		return arg
+	case *object.Float:
+		// float64(math.MaxInt64) rounds up to 2^63, which is out of range itself.
+		f := math.Trunc(arg.Value)
+		if math.IsNaN(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+			return newError("cannot convert %s to Integer", arg.Inspect())
+		}
+		return &object.Integer{Value: int64(f)}
+	case *object.String:
+		i, err := strconv.ParseInt(arg.Value, 10, 64)
+		if err != nil {
+			return newError("could not parse %q as Integer", arg.Value)
+		}
+		return &object.Integer{Value: i}
+	case *object.Boolean:
+		if arg.Value {
+			return &object.Integer{Value: 1}
+		}
+		return &object.Integer{Value: 0}
+	default:
+		return newError("argument to `int` not supported, got %s", arg.Type())
+	}
+}
+
+// decimalPattern matches decimal numbers with an optional exponent. Unlike strconv.ParseFloat, it
+// rejects hexadecimal numbers, underscores between digits, and names such as "Inf".
+var decimalPattern = regexp.MustCompile(`^[+-]?([0-9]+\.?[0-9]*|\.[0-9]+)([eE][+-]?[0-9]+)?$`)
+
+// builtinFloat converts its argument to a Float. Strings must hold a finite decimal number and
+// nothing else.
+func builtinFloat(args ...object.Object) object.Object {
+	if err := checkArgs(args, 1, 1); err != nil {
+		return err
+	}
+
+	switch arg := args[0].(type) {
+	case *object.Float:
+		return arg
+	case *object.Integer:
+		return &object.Float{Value: float64(arg.Value)}
+	case *object.String:
+		if !decimalPattern.MatchString(arg.Value) {
+			return newError("could not parse %q as Float", arg.Value)
+		}
+		f, err := strconv.ParseFloat(arg.Value, 64)
+		if err != nil || math.IsInf(f, 0) {
+			return newError("could not parse %q as Float", arg.Value)
+		}
+		return &object.Float{Value: f}
+	default:
+		return newError("argument to `float` not supported, got %s", arg.Type())
+	}
+}
+
+// builtinStr converts its argument to a String in the same format as it is printed.
+func builtinStr(args ...object.Object) object.Object {
+	if err := checkArgs(args, 1, 1); err != nil {
+		return err
+	}
+
+	if str, ok := args[0].(*object.String); ok {
+		return str
+	}
+	return &object.String{Value: args[0].Inspect()}
+}
+
+// builtinBool converts its argument to a Boolean. Numbers are true unless they are zero, nil is
+// false, and strings must be either "true" or "false".
+func builtinBool(args ...object.Object) object.Object {
+	if err := checkArgs(args, 1, 1); err != nil {
+		return err
+	}
+
+	switch arg := args[0].(type) {
+	case *object.Boolean:
+		return arg
+	case *object.Nil:
+		return FalseValue
+	case *object.Integer:
+		return nativeBoolToBooleanObject(arg.Value != 0)
+	case *object.Float:
+		return nativeBoolToBooleanObject(arg.Value != 0)
+	case *object.String:
+		switch arg.Value {
+		case "true":
+			return TrueValue
+		case "false":
+			return FalseValue
+		default:
+			return newError("could not parse %q as Boolean", arg.Value)
+		}
+	default:
+		return newError("argument to `bool` not supported, got %s", arg.Type())
+	}
+}
@@ -2,6 +2,9 @@ package eval
 
 import (
 	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/skatsuta/monkey-interpreter/ast"
 	"github.com/skatsuta/monkey-interpreter/object"
@@ -37,12 +40,17 @@ func Eval(node ast.Node, env object.Environment) object.Object {
 	case *ast.BlockStatement:
 		return evalBlockStatement(node, env)
 
+	case *ast.ThrowStatement:
+		return evalThrowStatement(node, env)
+
 	case *ast.LetStatement:
 		value := Eval(node.Value, env)
 		if isError(value) {
 			return value
 		}
-		env.Set(node.Name.Value, value)
+		if err := bind(node.Name, value, env, node.IsConst()); err != nil {
+			return err
+		}
 
 	// Expressions
 
@@ -76,12 +84,29 @@ func Eval(node ast.Node, env object.Environment) object.Object {
 	case *ast.IfExpression:
 		return evalIfExpression(node, env)
 
+	case *ast.ConditionalExpression:
+		condition := Eval(node.Condition, env)
+		if isError(condition) {
+			return condition
+		}
+		if isTruthy(condition) {
+			return Eval(node.Consequence, env)
+		}
+		return Eval(node.Alternative, env)
+
+	case *ast.TryExpression:
+		return evalTryExpression(node, env)
+
+	case *ast.MatchExpression:
+		return evalMatchExpression(node, env)
+
 	case *ast.Ident:
 		return evalIdent(node, env)
 
 	case *ast.FunctionLiteral:
 		return &object.Function{
 			Parameters: node.Parameters,
+			Rest:       node.Rest,
 			Body:       node.Body,
 			Env:        env,
 		}
@@ -91,6 +116,10 @@ func Eval(node ast.Node, env object.Environment) object.Object {
 			return quote(node.Arguments[0], env)
 		}
 
+		if member, ok := node.Function.(*ast.MemberExpression); ok {
+			return evalMethodCall(node, member, env)
+		}
+
 		function := Eval(node.Function, env)
 		if isError(function) {
 			return function
@@ -101,7 +130,7 @@ func Eval(node ast.Node, env object.Environment) object.Object {
 			return args[0]
 		}
 
-		return applyFunction(function, args)
+		return withFrame(applyFunction(function, args), node)
 
 	case *ast.StringLiteral:
 		return &object.String{Value: node.Value}
@@ -124,8 +153,21 @@ func Eval(node ast.Node, env object.Environment) object.Object {
 		}
 		return evalIndexExpression(left, index)
 
+	case *ast.SliceExpression:
+		return evalSliceExpression(node, env)
+
+	case *ast.MemberExpression:
+		left := Eval(node.Left, env)
+		if isError(left) {
+			return left
+		}
+		return evalMemberExpression(left, node.Member.Value)
+
 	case *ast.HashLiteral:
 		return evalHashLiteral(node, env)
+
+	case *ast.SpreadExpression:
+		return newError("spread not allowed here: %s", node)
 	}
 
 	return nil
@@ -186,16 +228,24 @@ func evalMinusPrefixOperatorExpression(right object.Object) object.Object {
 
 func evalInfixExpression(operator string, left, right object.Object) object.Object {
 	switch {
+	case operator == "==":
+		return nativeBoolToBooleanObject(object.Equal(left, right))
+	case operator == "!=":
+		return nativeBoolToBooleanObject(!object.Equal(left, right))
+	case operator == "in":
+		return evalInExpression(left, right)
 	case left.Type() == object.IntegerType && right.Type() == object.IntegerType:
 		return evalIntegerInfixExpression(operator, left, right)
 	case left.Type() == object.FloatType || right.Type() == object.FloatType:
 		return evalFloatInfixExpression(operator, left, right)
 	case left.Type() == object.StringType && right.Type() == object.StringType:
 		return evalStringInfixExpression(operator, left, right)
-	case operator == "==":
-		return nativeBoolToBooleanObject(left == right)
-	case operator == "!=":
-		return nativeBoolToBooleanObject(left != right)
+	case left.Type() == object.ArrayType && right.Type() == object.ArrayType:
+		return evalArrayInfixExpression(operator, left, right)
+	case left.Type() == object.HashType && right.Type() == object.HashType:
+		return evalHashInfixExpression(operator, left, right)
+	case operator == "*" && (left.Type() == object.IntegerType || right.Type() == object.IntegerType):
+		return evalRepetition(left, right)
 	case left.Type() != right.Type():
 		return newError("type mismatch: %s %s %s", left.Type(), operator, right.Type())
 	default:
@@ -224,10 +274,6 @@ func evalIntegerInfixExpression(operator string, left, right object.Object) obje
 		return nativeBoolToBooleanObject(leftVal <= rightVal)
 	case ">=":
 		return nativeBoolToBooleanObject(leftVal >= rightVal)
-	case "==":
-		return nativeBoolToBooleanObject(leftVal == rightVal)
-	case "!=":
-		return nativeBoolToBooleanObject(leftVal != rightVal)
 	default:
 		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
@@ -263,6 +309,25 @@ func evalFloatInfixExpression(operator string, left, right object.Object) object
 		return &object.Float{Value: leftVal * rightVal}
 	case "/":
 		return &object.Float{Value: leftVal / rightVal}
+	case "<", ">", "<=", ">=":
+		// NaN is unordered, so that any comparison with it is false. Other numbers are compared
+		// exactly by object.Compare, since converting a large Integer to a Float may round it.
+		if math.IsNaN(leftVal) || math.IsNaN(rightVal) {
+			return FalseValue
+		}
+		return evalComparison(operator, left, right)
+	default:
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+func evalStringInfixExpression(operator string, left, right object.Object) object.Object {
+	leftVal := left.(*object.String).Value
+	rightVal := right.(*object.String).Value
+
+	switch operator {
+	case "+":
+		return &object.String{Value: leftVal + rightVal}
 	case "<":
 		return nativeBoolToBooleanObject(leftVal < rightVal)
 	case ">":
@@ -271,36 +336,138 @@ func evalFloatInfixExpression(operator string, left, right object.Object) object
 		return nativeBoolToBooleanObject(leftVal <= rightVal)
 	case ">=":
 		return nativeBoolToBooleanObject(leftVal >= rightVal)
-	case "==":
-		return nativeBoolToBooleanObject(leftVal == rightVal)
-	case "!=":
-		return nativeBoolToBooleanObject(leftVal != rightVal)
 	default:
 		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
 }
 
-func evalStringInfixExpression(operator string, left, right object.Object) object.Object {
-	leftVal := left.(*object.String).Value
-	rightVal := right.(*object.String).Value
+// maxRepeatLength is the maximum length of a string or an array built by repeating another one, so
+// that a huge count fails with an error instead of exhausting memory.
+const maxRepeatLength = 1 << 26
 
+// repeatFits reports whether `n` repetitions of something of length `size` are within
+// maxRepeatLength.
+func repeatFits(size int, n int64) bool {
+	return size == 0 || n <= int64(maxRepeatLength/size)
+}
+
+// evalRepetition evaluates `"ab" * 3`, `[0] * 3` and the same with operands swapped.
+func evalRepetition(left, right object.Object) object.Object {
+	seq, count := left, right
+	if left.Type() == object.IntegerType {
+		seq, count = right, left
+	}
+
+	n := count.(*object.Integer).Value
+	if n < 0 {
+		return newError("negative repeat count: %d", n)
+	}
+
+	switch seq := seq.(type) {
+	case *object.String:
+		if !repeatFits(len(seq.Value), n) {
+			return newError("repeat count too large: %d", n)
+		}
+		return &object.String{Value: strings.Repeat(seq.Value, int(n))}
+	case *object.Array:
+		if len(seq.Elements) == 0 {
+			return &object.Array{Elements: []object.Object{}}
+		}
+		if !repeatFits(len(seq.Elements), n) {
+			return newError("repeat count too large: %d", n)
+		}
+		elems := make([]object.Object, 0, len(seq.Elements)*int(n))
+		for i := int64(0); i < n; i++ {
+			elems = append(elems, seq.Elements...)
+		}
+		return &object.Array{Elements: elems}
+	default:
+		return newError("type mismatch: %s * %s", left.Type(), right.Type())
+	}
+}
+
+func evalArrayInfixExpression(operator string, left, right object.Object) object.Object {
 	switch operator {
 	case "+":
-		return &object.String{Value: leftVal + rightVal}
-	case "==":
-		return nativeBoolToBooleanObject(leftVal == rightVal)
-	case "!=":
-		return nativeBoolToBooleanObject(leftVal != rightVal)
+		leftElems := left.(*object.Array).Elements
+		rightElems := right.(*object.Array).Elements
+		elems := make([]object.Object, 0, len(leftElems)+len(rightElems))
+		elems = append(elems, leftElems...)
+		elems = append(elems, rightElems...)
+		return &object.Array{Elements: elems}
+	case "<", ">", "<=", ">=":
+		return evalComparison(operator, left, right)
+	default:
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+func evalHashInfixExpression(operator string, left, right object.Object) object.Object {
+	switch operator {
+	case "+":
+		return mergeHashes(left.(*object.Hash), right.(*object.Hash))
 	default:
 		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
 }
 
+// evalInExpression evaluates the membership operator: `x in array` checks whether the array has an
+// element equal to x, `s in string` whether s is a substring of the string, and `key in hash`
+// whether the hash has the key.
+func evalInExpression(left, right object.Object) object.Object {
+	switch right := right.(type) {
+	case *object.Array:
+		for _, elem := range right.Elements {
+			if object.Equal(left, elem) {
+				return TrueValue
+			}
+		}
+		return FalseValue
+	case *object.String:
+		str, ok := left.(*object.String)
+		if !ok {
+			return newError("type mismatch: %s in %s", left.Type(), right.Type())
+		}
+		return nativeBoolToBooleanObject(strings.Contains(right.Value, str.Value))
+	case *object.Hash:
+		key, ok := left.(object.Hashable)
+		if !ok {
+			return newError("unusable as hash key: %s", left.Type())
+		}
+		_, exists := right.Get(key)
+		return nativeBoolToBooleanObject(exists)
+	default:
+		return newError("unknown operator: %s in %s", left.Type(), right.Type())
+	}
+}
+
+// evalComparison evaluates an ordering operator using object.Compare.
+func evalComparison(operator string, left, right object.Object) object.Object {
+	c, err := object.Compare(left, right)
+	if err != nil {
+		return newError("%s", err)
+	}
+
+	switch operator {
+	case "<":
+		return nativeBoolToBooleanObject(c < 0)
+	case ">":
+		return nativeBoolToBooleanObject(c > 0)
+	case "<=":
+		return nativeBoolToBooleanObject(c <= 0)
+	default:
+		return nativeBoolToBooleanObject(c >= 0)
+	}
+}
+
+// evalBlockStatement evaluates the statements of `block` in a new scope, so that their bindings are
+// not visible outside of the block.
 func evalBlockStatement(block *ast.BlockStatement, env object.Environment) object.Object {
 	var result object.Object
 
+	scope := object.NewEnclosedEnvironment(env)
 	for _, stmt := range block.Statements {
-		result = Eval(stmt, env)
+		result = Eval(stmt, scope)
 		if result == nil {
 			continue
 		}
@@ -348,6 +515,14 @@ func evalIdent(node *ast.Ident, env object.Environment) object.Object {
 		return builtin
 	}
 
+	if newBuiltin, ok := envBuiltins[node.Value]; ok {
+		return &object.Builtin{Fn: newBuiltin(env), Name: node.Value}
+	}
+
+	if constant, ok := constants[node.Value]; ok {
+		return constant
+	}
+
 	return newError("identifier not found: %s", node.Value)
 }
 
@@ -355,30 +530,64 @@ func evalExpressions(exprs []ast.Expression, env object.Environment) []object.Ob
 	result := make([]object.Object, 0, len(exprs))
 
 	for _, expr := range exprs {
+		spread, isSpread := expr.(*ast.SpreadExpression)
+		if isSpread {
+			expr = spread.Value
+		}
+
 		evaluated := Eval(expr, env)
 		if isError(evaluated) {
 			return []object.Object{evaluated}
 		}
-		result = append(result, evaluated)
+
+		if !isSpread {
+			result = append(result, evaluated)
+			continue
+		}
+
+		arr, ok := evaluated.(*object.Array)
+		if !ok {
+			return []object.Object{newError("cannot spread %s, want Array", evaluated.Type())}
+		}
+		result = append(result, arr.Elements...)
 	}
 
 	return result
 }
 
-func extendFunctionEnv(fn *object.Function, args []object.Object) object.Environment {
+func extendFunctionEnv(fn *object.Function, args []object.Object) (object.Environment, *object.Error) {
 	env := object.NewEnclosedEnvironment(fn.Env)
 
 	for i, param := range fn.Parameters {
-		env.Set(param.Value, args[i])
+		if err := bind(param, args[i], env, false); err != nil {
+			return nil, err
+		}
+	}
+
+	if fn.Rest != nil {
+		rest := make([]object.Object, len(args)-len(fn.Parameters))
+		copy(rest, args[len(fn.Parameters):])
+		if err := bind(fn.Rest, &object.Array{Elements: rest}, env, false); err != nil {
+			return nil, err
+		}
 	}
 
-	return env
+	return env, nil
 }
 
 func applyFunction(fn object.Object, args []object.Object) object.Object {
 	switch fn := fn.(type) {
 	case *object.Function:
-		extendedEnv := extendFunctionEnv(fn, args)
+		switch want, got := len(fn.Parameters), len(args); {
+		case fn.Rest == nil && got != want:
+			return newError("wrong number of arguments. want=%d, got=%d", want, got)
+		case got < want:
+			return newError("wrong number of arguments. want at least %d, got=%d", want, got)
+		}
+		extendedEnv, err := extendFunctionEnv(fn, args)
+		if err != nil {
+			return err
+		}
 		evaluated := Eval(fn.Body, extendedEnv)
 		return unwrapReturnValue(evaluated)
 	case *object.Builtin:
@@ -399,30 +608,200 @@ func evalIndexExpression(left, index object.Object) object.Object {
 	switch {
 	case left.Type() == object.ArrayType && index.Type() == object.IntegerType:
 		return evalArrayIndexExpression(left, index)
+	case left.Type() == object.StringType && index.Type() == object.IntegerType:
+		return evalStringIndexExpression(left, index)
 	case left.Type() == object.HashType:
 		return evalHashIndexExpression(left, index)
+	case left.Type() == object.ModuleType && index.Type() == object.StringType:
+		return evalModuleIndexExpression(left, index)
 	default:
 		return newError("index operator not supported: %s", left.Type())
 	}
 }
 
+// evalModuleIndexExpression looks up a top-level binding of a module by its name.
+func evalModuleIndexExpression(module, name object.Object) object.Object {
+	mod := module.(*object.Module)
+	if value, ok := mod.Exports.Get(name.(*object.String)); ok {
+		return value
+	}
+	return newError("module %s has no member %s", mod.Path, name.Inspect())
+}
+
+// evalMemberExpression evaluates `left.name`, which is the same as `left["name"]` for hashes and
+// modules.
+func evalMemberExpression(left object.Object, name string) object.Object {
+	key := &object.String{Value: name}
+	switch left.Type() {
+	case object.HashType:
+		return evalHashIndexExpression(left, key)
+	case object.ModuleType:
+		return evalModuleIndexExpression(left, key)
+	default:
+		return newError("member access not supported: %s", left.Type())
+	}
+}
+
+// evalMethodCall evaluates `receiver.name(args)`. If the receiver is a module or a hash with the key
+// "name", the member is called with `args`. Otherwise the function `name` is called with the
+// receiver followed by `args`, so that `xs.map(f)` is the same as `map(xs, f)`.
+func evalMethodCall(call *ast.CallExpression, node *ast.MemberExpression, env object.Environment) object.Object {
+	receiver := Eval(node.Left, env)
+	if isError(receiver) {
+		return receiver
+	}
+
+	var function object.Object
+	isMember := false
+	switch recv := receiver.(type) {
+	case *object.Module:
+		function, isMember = evalModuleIndexExpression(recv, &object.String{Value: node.Member.Value}), true
+	case *object.Hash:
+		function, isMember = recv.Get(&object.String{Value: node.Member.Value})
+	}
+	if !isMember {
+		function = evalIdent(node.Member, env)
+	}
+	if isError(function) {
+		return function
+	}
+
+	evaluated := evalExpressions(call.Arguments, env)
+	if len(evaluated) == 1 && isError(evaluated[0]) {
+		return evaluated[0]
+	}
+
+	if !isMember {
+		evaluated = append([]object.Object{receiver}, evaluated...)
+	}
+	return withFrame(applyFunction(function, evaluated), call)
+}
+
+// normalizeIndex converts a possibly negative index counted from the end of a sequence of length
+// `length` into a position from its start. It returns false if the index is out of range.
+func normalizeIndex(idx int64, length int) (int, bool) {
+	if idx < 0 {
+		idx += int64(length)
+	}
+	if idx < 0 || idx >= int64(length) {
+		return 0, false
+	}
+	return int(idx), true
+}
+
 func evalArrayIndexExpression(array, index object.Object) object.Object {
 	arrObj := array.(*object.Array)
-	idx := index.(*object.Integer).Value
-	max := int64(len(arrObj.Elements) - 1)
-
-	if idx < 0 || idx > max {
+	idx, ok := normalizeIndex(index.(*object.Integer).Value, len(arrObj.Elements))
+	if !ok {
 		return NilValue
 	}
 
 	return arrObj.Elements[idx]
 }
 
+// evalStringIndexExpression returns the character at the index of a string. Strings are indexed by
+// Unicode code points rather than bytes.
+func evalStringIndexExpression(str, index object.Object) object.Object {
+	runes := []rune(str.(*object.String).Value)
+	idx, ok := normalizeIndex(index.(*object.Integer).Value, len(runes))
+	if !ok {
+		return NilValue
+	}
+
+	return &object.String{Value: string(runes[idx])}
+}
+
+func evalSliceExpression(node *ast.SliceExpression, env object.Environment) object.Object {
+	left := Eval(node.Left, env)
+	if isError(left) {
+		return left
+	}
+
+	var length int
+	switch left := left.(type) {
+	case *object.Array:
+		length = len(left.Elements)
+	case *object.String:
+		length = utf8.RuneCountInString(left.Value)
+	default:
+		return newError("slice operator not supported: %s", left.Type())
+	}
+
+	low, err := evalSliceBound(node.Low, env, 0, length)
+	if err != nil {
+		return err
+	}
+	high, err := evalSliceBound(node.High, env, length, length)
+	if err != nil {
+		return err
+	}
+	if high < low {
+		high = low
+	}
+
+	switch left := left.(type) {
+	case *object.Array:
+		elems := make([]object.Object, high-low)
+		copy(elems, left.Elements[low:high])
+		return &object.Array{Elements: elems}
+	default:
+		runes := []rune(left.(*object.String).Value)
+		return &object.String{Value: string(runes[low:high])}
+	}
+}
+
+// evalSliceBound evaluates a bound of a slice expression over a sequence of length `length`.
+// An omitted bound defaults to `def`, a negative one counts from the end, and a bound out of range
+// is clamped to the sequence.
+func evalSliceBound(expr ast.Expression, env object.Environment, def, length int) (int, *object.Error) {
+	if expr == nil {
+		return def, nil
+	}
+
+	bound := Eval(expr, env)
+	if errObj, ok := bound.(*object.Error); ok {
+		return 0, errObj
+	}
+
+	integer, ok := bound.(*object.Integer)
+	if !ok {
+		return 0, newError("slice index must be Integer, got %s", bound.Type())
+	}
+
+	idx := integer.Value
+	if idx < 0 {
+		idx += int64(length)
+	}
+	switch {
+	case idx < 0:
+		return 0, nil
+	case idx > int64(length):
+		return length, nil
+	default:
+		return int(idx), nil
+	}
+}
+
 func evalHashLiteral(node *ast.HashLiteral, env object.Environment) object.Object {
-	pairs := make(map[object.HashKey]object.HashPair, len(node.Pairs))
+	hash := object.NewHash(len(node.Pairs))
+
+	for _, pair := range node.Pairs {
+		if spread, ok := pair.Key.(*ast.SpreadExpression); ok {
+			value := Eval(spread.Value, env)
+			if isError(value) {
+				return value
+			}
+			h, ok := value.(*object.Hash)
+			if !ok {
+				return newError("cannot spread %s, want Hash", value.Type())
+			}
+			for _, p := range h.Pairs() {
+				hash.Set(p.Key.(object.Hashable), p.Value)
+			}
+			continue
+		}
 
-	for keyNode, valueNode := range node.Pairs {
-		key := Eval(keyNode, env)
+		key := Eval(pair.Key, env)
 		if isError(key) {
 			return key
 		}
@@ -432,19 +811,15 @@ func evalHashLiteral(node *ast.HashLiteral, env object.Environment) object.Objec
 			return newError("unusable as hash key: %s", key.Type())
 		}
 
-		value := Eval(valueNode, env)
+		value := Eval(pair.Value, env)
 		if isError(value) {
 			return value
 		}
 
-		hashed := hashKey.HashKey()
-		pairs[hashed] = object.HashPair{
-			Key:   key,
-			Value: value,
-		}
+		hash.Set(hashKey, value)
 	}
 
-	return &object.Hash{Pairs: pairs}
+	return hash
 }
 
 func evalHashIndexExpression(left, index object.Object) object.Object {
@@ -454,8 +829,8 @@ func evalHashIndexExpression(left, index object.Object) object.Object {
 	}
 
 	hashObj := left.(*object.Hash)
-	if pair, exists := hashObj.Pairs[key.HashKey()]; exists {
-		return pair.Value
+	if value, exists := hashObj.Get(key); exists {
+		return value
 	}
 	return NilValue
 }
@@ -1,6 +1,8 @@
 package eval
 
 import (
+	"bytes"
+	"io/ioutil"
 	"strings"
 	"testing"
 
@@ -18,7 +20,7 @@ func testEval(t *testing.T, input string) object.Object {
 		t.Fatalf("input %q has errors: \n%v", input, strings.Join(p.Errors(), "\n"))
 	}
 
-	env := object.NewEnvironment()
+	env := object.NewEnvironment(object.WithOutput(ioutil.Discard))
 	return Eval(program, env)
 }
 
@@ -131,6 +133,53 @@ func TestEvalBooleanExpression(t *testing.T) {
 		{`"hello" == "world"`, false},
 		{`"foo" != "bar"`, true},
 		{`"foo" != "foo"`, false},
+		{"1 == 1.0", true},
+		{"1.5 != 1", true},
+		{"let a = 9007199254740993; let b = 9007199254740992.0; a > b", true},
+		{"let a = 9007199254740993; let b = 9007199254740992.0; a >= b", true},
+		{"let a = 9007199254740993; let b = 9007199254740992.0; a <= b", false},
+		{"let a = 9007199254740993; let b = 9007199254740992.0; a < b", false},
+		{"let a = 9007199254740993; let b = 9007199254740992.0; b < a", true},
+		{"let a = 9007199254740993; let b = 9007199254740992.0; compare(a, b) == 1", true},
+		{"9223372036854775807 < 9223372036854775808.0", true},
+		{"1 < NAN", false},
+		{"NAN >= 1", false},
+		{"NAN <= NAN", false},
+		{"1.5 <= 1.5", true},
+		{"-0.0 >= 0.0", true},
+		{"[1, 2] == [1, 2]", true},
+		{"[1, 2] == [2, 1]", false},
+		{"[1, [2, 3]] != [1, [2, 3]]", false},
+		{`{"a": 1, "b": 2} == {"b": 2, "a": 1}`, true},
+		{`{"a": 1} == {"a": 2}`, false},
+		{`[] == {}`, false},
+		{"let f = fn() {}; f == f", true},
+		{"fn() {} == fn() {}", false},
+		{"len == len", true},
+		{"puts == puts", true},
+		{"let p = puts; p == puts", true},
+		{"let f = fn() { puts }; f() == puts", true},
+		{"puts != print", true},
+		{"puts == len", false},
+		{"if (false) {} == if (false) {}", true},
+		{"1 == true", false},
+		{"1 <= 1", true},
+		{"2 >= 3", false},
+		{"1.5 <= 1", false},
+		{`"a" < "b"`, true},
+		{`"b" < "a"`, false},
+		{`"a" < "ab"`, true},
+		{`"B" < "a"`, true},
+		{`"abc" > "abd"`, false},
+		{`"abc" >= "abc"`, true},
+		{`"abc" <= "ab"`, false},
+		{"[1, 2] < [1, 3]", true},
+		{"[1, 2] < [1, 2, 0]", true},
+		{"[2] > [1, 5]", true},
+		{"[] <= []", true},
+		{"[1, 2.5] >= [1, 2]", true},
+		{`[1, "b"] > [1, "a"]`, true},
+		{`[[1, 2], [3]] < [[1, 2], [4]]`, true},
 	}
 
 	for _, tt := range tests {
@@ -170,6 +219,11 @@ func TestIfExpression(t *testing.T) {
 		{"if (1 > 2) { 10 }", nil},
 		{"if (1 < 2) { 10 } else { 20 }", 10},
 		{"if (1 > 2) { 10 } else { 20 }", 20},
+		{"if (1 > 2) { 10 } else if (2 > 1) { 20 } else { 30 }", 20},
+		{"if (1 > 2) { 10 } else if (2 > 3) { 20 } else { 30 }", 30},
+		{"if (1 > 2) { 10 } else if (2 > 3) { 20 }", nil},
+		{"if (1 < 2) { 10 } else if (1 / 0) { 20 }", 10},
+		{"let f = fn(x) { if (x < 0) { -1 } else if (x == 0) { 0 } else { 1 } }; f(0) + f(5) * 10", 10},
 	}
 
 	for _, tt := range tests {
@@ -188,6 +242,159 @@ func testNilObject(t *testing.T, obj object.Object) {
 	}
 }
 
+func TestConditionalExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"true ? 1 : 2", "1"},
+		{"false ? 1 : 2", "2"},
+		{"[][0] ? 1 : 2", "2"},
+		{"0 ? 1 : 2", "1"},
+		{"let x = 5; x > 3 ? \"big\" : \"small\"", "big"},
+		{"let sign = fn(x) { x < 0 ? -1 : x == 0 ? 0 : 1 }; [sign(-5), sign(0), sign(5)]", "[-1, 0, 1]"},
+		{"1 + (true ? 2 : 3) * 2", "5"},
+		{"true ? 1 : 1 / 0", "1"},
+		{"false ? 1 / 0 : 2", "2"},
+		{"x ? 1 : 2", "Error: identifier not found: x"},
+		{"true ? 1 + true : 2", "Error: type mismatch: Integer + Boolean"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		if got := evaluated.Inspect(); got != tt.expected {
+			t.Errorf("%s: expected %q, but got %q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestPipeExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"[1, 2, 3] |> len", "3"},
+		{"[1, 2, 3, 4] |> map(fn(x) { x * x }) |> filter(fn(x) { x > 4 }) |> reduce(fn(a, b) { a + b }, 0)", "25"},
+		{`"a,b,c" |> split(",") |> join("-")`, "a-b-c"},
+		{"let inc = fn(x) { x + 1 }; 1 |> inc |> inc", "3"},
+		{"let add = fn(a, b) { a + b }; 1 |> add(2)", "3"},
+		{"5 |> fn(x) { x * 2 }", "10"},
+		{"1 + 2 |> str", "3"},
+		{"[1, 2] |> len == 2", "true"},
+		{"let fs = [fn(x) { -x }]; 3 |> fs[0]", "-3"},
+		{`let h = {"f": fn(x) { x * 10 }}; 2 |> h.f`, "20"},
+		{`let h = {"f": fn(x, y) { x - y }}; 5 |> h.f(1)`, "4"},
+		{"[1] |> push(...[2])", "[1, 2]"},
+		{"1 |> 2", "Error: not a function: Integer"},
+		{"1 |> len(2)", "Error: wrong number of arguments. want=1, got=2"},
+		{"x |> len", "Error: identifier not found: x"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		if got := evaluated.Inspect(); got != tt.expected {
+			t.Errorf("%s: expected %q, but got %q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestConstStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"const a = 5; a", "5"},
+		{"const [a, b] = [1, 2]; a + b", "3"},
+		{`const {x, y} = {"x": 1, "y": 2}; x * 10 + y`, "12"},
+		{"const a = 1; let a = 2;", "Error: cannot redeclare constant a"},
+		{"const a = 1; const a = 2;", "Error: cannot redeclare constant a"},
+		{"const a = 1; let [b, a] = [2, 3];", "Error: cannot redeclare constant a"},
+		{"let a = 1; const a = 2; a", "2"},
+		{"const a = 1; let [_, b] = [2, 3]; b", "3"},
+		{"const a = 1; let f = fn() { let a = 2; a }; [f(), a]", "[2, 1]"},
+		{"const a = 1; let f = fn(a) { a }; f(2)", "2"},
+		{"const a = 1; if (true) { const a = 2; a }", "2"},
+		{"const a = 1; match (2) { a => a }", "2"},
+		{"const a = 1; try { throw 2 } catch (a) { a.value }", "2"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		if got := evaluated.Inspect(); got != tt.expected {
+			t.Errorf("%s: expected %q, but got %q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestBlockScoping(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"if (true) { let x = 1 }; x", "Error: identifier not found: x"},
+		{"if (false) { 1 } else { let x = 2 }; x", "Error: identifier not found: x"},
+		{"let x = 1; if (true) { let x = 2; x }", "2"},
+		{"let x = 1; if (true) { let x = 2 }; x", "1"},
+		{"let x = 1; if (true) { let y = x + 1; if (true) { y * 10 } }", "20"},
+		{"let f = fn() { if (true) { let x = 1 }; x }; f()", "Error: identifier not found: x"},
+		{"try { let x = 1 } finally { 2 }; x", "Error: identifier not found: x"},
+		{"try { throw 1 } catch (e) { let x = 1 }; x", "Error: identifier not found: x"},
+		{"let f = fn() { if (true) { return 1 }; 2 }; f()", "1"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		if got := evaluated.Inspect(); got != tt.expected {
+			t.Errorf("%s: expected %q, but got %q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestStrictBuiltins(t *testing.T) {
+	tests := []struct {
+		input   string
+		strict  string
+		lenient string
+	}{
+		{"let len = 1; len", "Error: cannot shadow builtin len", "1"},
+		{"const first = 1; first", "Error: cannot shadow builtin first", "1"},
+		{"let puts = 1; puts", "Error: cannot shadow builtin puts", "1"},
+		{"let PI = 3; PI", "Error: cannot shadow builtin PI", "3"},
+		{"let [a, len] = [1, 2]; a", "Error: cannot shadow builtin len", "1"},
+		{"let f = fn(len) { len }; f(1)", "Error: cannot shadow builtin len", "1"},
+		{"let f = fn(...args) { args }; f(1)", "[1]", "[1]"},
+		{"let f = fn(...rest) { rest }; f(1)", "Error: cannot shadow builtin rest", "[1]"},
+		{"if (true) { let len = 1; len }", "Error: cannot shadow builtin len", "1"},
+		{"match ([1]) { [len] => len }", "Error: cannot shadow builtin len", "1"},
+		{`match ({"a": 1}) { {a: first} => first }`, "Error: cannot shadow builtin first", "1"},
+		{"match ([1]) { [_] => 2 }", "2", "2"},
+		{"match (1) { 2 => 0, len => len }", "Error: cannot shadow builtin len", "1"},
+		{"try { throw 1 } catch (len) { len.value }", "Error: cannot shadow builtin len", "1"},
+		{"try { throw 1 } catch (e) { e.value }", "1", "1"},
+		{"let length = 1; length", "1", "1"},
+	}
+
+	for _, tt := range tests {
+		p := parser.New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		if len(p.Errors()) > 0 {
+			t.Fatalf("input %q has errors: \n%v", tt.input, strings.Join(p.Errors(), "\n"))
+		}
+
+		for _, strict := range []bool{true, false} {
+			expected := tt.lenient
+			if strict {
+				expected = tt.strict
+			}
+
+			env := object.NewEnvironment(object.WithOutput(ioutil.Discard), object.WithStrictBuiltins(strict))
+			if got := Eval(program, env).Inspect(); got != expected {
+				t.Errorf("%s (strict=%t): expected %q, but got %q", tt.input, strict, expected, got)
+			}
+		}
+	}
+}
+
 func TestReturnStatements(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -238,7 +445,17 @@ func TestErrorHandling(t *testing.T) {
 		{`"Hello" - "World"`, "unknown operator: String - String"},
 		{`1.5 + "World"`, "unknown operator: Float + String"},
 		{`{[1, 2]: "Monkey"}`, "unusable as hash key: Array"},
+		{`[1] < ["a"]`, "cannot compare Integer with String"},
+		{`[1] - [2]`, "unknown operator: Array - Array"},
+		{`{} - {}`, "unknown operator: Hash - Hash"},
+		{`[1] + {}`, "type mismatch: Array + Hash"},
+		{`1 in "abc"`, "type mismatch: Integer in String"},
+		{`[1] in {}`, "unusable as hash key: Array"},
+		{`1 in 2`, "unknown operator: Integer in Integer"},
+		{`"a" < 1`, "type mismatch: String < Integer"},
 		{`{"name": "Monkey"}[fn(x) { x }]`, "unusable as hash key: Function"},
+		{"fn(x, y) { x }(1)", "wrong number of arguments. want=2, got=1"},
+		{"fn() { 1 }(1)", "wrong number of arguments. want=0, got=1"},
 	}
 
 	for _, tt := range tests {
@@ -361,6 +578,8 @@ func TestBuiltinFunctions(t *testing.T) {
 		{`len("four")`, 4},
 		{`len("hello world")`, 11},
 		{`len("hello" + " " + "world")`, 11},
+		{`len("héllo")`, 5},
+		{`len("∑")`, 1},
 		{`len(1)`, "argument to `len` not supported, got Integer"},
 		{`len("one", "two")`, "wrong number of arguments. want=1, got=2"},
 		// len for arrays
@@ -387,6 +606,20 @@ func TestBuiltinFunctions(t *testing.T) {
 		{"push([1, 2], 3)", []int64{1, 2, 3}},
 		{"push([])", "wrong number of arguments. want=2, got=1"},
 		{"push(1, 2)", "first argument to `push` must be Array, got Integer"},
+		// compare
+		{"compare(1, 2)", -1},
+		{"compare(2.5, 2)", 1},
+		{`compare("a", "a")`, 0},
+		{`compare(["a", 2], ["a", 1])`, 1},
+		{`compare(1, "a")`, "cannot compare Integer with String"},
+		{"compare(1)", "wrong number of arguments. want=2, got=1"},
+		// collate
+		{`collate("apple", "Banana")`, -1},
+		{`collate("Banana", "apple")`, 1},
+		{`collate("b", "B")`, 1},
+		{`collate("Éclair", "éclair")`, -1},
+		{`collate("straße", "STRASSE")`, 1},
+		{`collate("a", 1)`, "second argument to `collate` must be String, got Integer"},
 		// puts
 		{"puts(1)", nil},
 	}
@@ -460,7 +693,9 @@ func TestArrayIndexExpressions(t *testing.T) {
 		{"let arr = [1, 2, 3]; arr[0] + arr[1] + arr[2];", 6},
 		{"let arr = [1, 2, 3]; let i = arr[0]; arr[i]", 2},
 		{"[1, 2, 3][3]", nil},
-		{"[1, 2, 3][-1]", nil},
+		{"[1, 2, 3][-1]", 3},
+		{"[1, 2, 3][-3]", 1},
+		{"[1, 2, 3][-4]", nil},
 	}
 
 	for _, tt := range tests {
@@ -473,6 +708,1003 @@ func TestArrayIndexExpressions(t *testing.T) {
 	}
 }
 
+func TestStringIndexExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"abc"[0]`, "a"},
+		{`"abc"[2]`, "c"},
+		{`"abc"[-1]`, "c"},
+		{`"abc"[-3]`, "a"},
+		{`let s = "héllo"; s[1] + s[4]`, "éo"},
+		{`"日本語"[1]`, "本"},
+		{`let s = "héllo"; s[len(s) - 1]`, "o"},
+		{`"abc"[3]`, nil},
+		{`"abc"[-4]`, nil},
+		{`""[0]`, nil},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		if s, ok := tt.expected.(string); ok {
+			testStringObject(t, evaluated, s)
+			continue
+		}
+		testNilObject(t, evaluated)
+	}
+}
+
+func testStringObject(t *testing.T, obj object.Object, expected string) {
+	str, ok := obj.(*object.String)
+	if !ok {
+		t.Errorf("object is not *object.String. got=%#v", obj)
+		return
+	}
+
+	if str.Value != expected {
+		t.Errorf("String has wrong value. want=%q, got=%q", expected, str.Value)
+	}
+}
+
+func TestSliceExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"[1, 2, 3, 4][1:3]", []int64{2, 3}},
+		{"[1, 2, 3, 4][:2]", []int64{1, 2}},
+		{"[1, 2, 3, 4][2:]", []int64{3, 4}},
+		{"[1, 2, 3, 4][:]", []int64{1, 2, 3, 4}},
+		{"[1, 2, 3, 4][-2:]", []int64{3, 4}},
+		{"[1, 2, 3, 4][:-1]", []int64{1, 2, 3}},
+		{"[1, 2, 3, 4][1:100]", []int64{2, 3, 4}},
+		{"[1, 2, 3, 4][-100:1]", []int64{1}},
+		{"[1, 2, 3, 4][3:1]", []int64{}},
+		{"[][0:1]", []int64{}},
+		{"let a = [1, 2, 3]; let n = 1; a[n:n + 1]", []int64{2}},
+		{`"hello"[1:3]`, "el"},
+		{`"hello"[:-2]`, "hel"},
+		{`"hello"[3:]`, "lo"},
+		{`"hello"[10:]`, ""},
+		{`"héllo wörld"[1:8]`, "éllo wö"},
+		{`"abc"[true:]`, "slice index must be Integer, got Boolean"},
+		{`"abc"[:foo]`, "identifier not found: foo"},
+		{`5[1:2]`, "slice operator not supported: Integer"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+
+		switch expected := tt.expected.(type) {
+		case []int64:
+			arr, ok := evaluated.(*object.Array)
+			if !ok {
+				t.Errorf("object is not *object.Array. got=%#v", evaluated)
+				continue
+			}
+			if len(arr.Elements) != len(expected) {
+				t.Errorf("wrong number of elements for %q. want=%d, got=%d",
+					tt.input, len(expected), len(arr.Elements))
+				continue
+			}
+			for i, elem := range arr.Elements {
+				testIntegerObject(t, elem, expected[i])
+			}
+		case string:
+			if errObj, ok := evaluated.(*object.Error); ok {
+				if errObj.Message != expected {
+					t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+				}
+				continue
+			}
+			testStringObject(t, evaluated, expected)
+		}
+	}
+}
+
+func TestSliceDoesNotShareArray(t *testing.T) {
+	input := `
+	let a = [1, 2, 3];
+	let b = push(a[0:2], 4);
+	[a, b]
+	`
+
+	evaluated := testEval(t, input)
+	want := "[[1, 2, 3], [1, 2, 4]]"
+	if got := evaluated.Inspect(); got != want {
+		t.Errorf("expected %s, but got %s", want, got)
+	}
+}
+
+func TestRepetition(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"ab" * 3`, "ababab"},
+		{`3 * "ab"`, "ababab"},
+		{`"ab" * 0`, ""},
+		{`"" * 5`, ""},
+		{"[0] * 3", "[0, 0, 0]"},
+		{"2 * [1, 2]", "[1, 2, 1, 2]"},
+		{"[1] * 0", "[]"},
+		{"[] * 9223372036854775807", "[]"},
+		{`"" * 9223372036854775807`, ""},
+		{`"ab" * 9223372036854775807`, "Error: repeat count too large: 9223372036854775807"},
+		{"[1] * 9223372036854775807", "Error: repeat count too large: 9223372036854775807"},
+		{"4611686018427387904 * [1, 2]", "Error: repeat count too large: 4611686018427387904"},
+		{`"ab" * 33554433`, "Error: repeat count too large: 33554433"},
+		{`"ab" * -1`, "Error: negative repeat count: -1"},
+		{"[1] * -2", "Error: negative repeat count: -2"},
+		{"true * 2", "Error: type mismatch: Boolean * Integer"},
+		{`"ab" * 1.5`, "Error: unknown operator: String * Float"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		if got := evaluated.Inspect(); got != tt.expected {
+			t.Errorf("%s: expected %q, but got %q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestConcatenation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"[1, 2] + [3]", "[1, 2, 3]"},
+		{"[] + []", "[]"},
+		{`[1] + ["a", [2]] + []`, "[1, a, [2]]"},
+		{`{"a": 1, "b": 2} + {"c": 3}`, "{a: 1, b: 2, c: 3}"},
+		{`{"a": 1, "b": 2} + {"a": 10, "c": 3}`, "{a: 10, b: 2, c: 3}"},
+		{`{} + {1: true}`, "{1: true}"},
+		{`let h = {"a": 1}; let m = h + {"a": 2}; [h, m]`, "[{a: 1}, {a: 2}]"},
+		{`let a = [1]; let b = a + [2]; [a, b]`, "[[1], [1, 2]]"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		if got := evaluated.Inspect(); got != tt.expected {
+			t.Errorf("%s: expected %q, but got %q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestInExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"1 in [1, 2]", true},
+		{"3 in [1, 2]", false},
+		{"1.0 in [1, 2]", true},
+		{"[1] in [[1], [2]]", true},
+		{"1 in []", false},
+		{`"ell" in "hello"`, true},
+		{`"" in "hello"`, true},
+		{`"world" in "hello"`, false},
+		{`"a" in {"a": [][0]}`, true},
+		{`"b" in {"a": 1}`, false},
+		{`1 in {1.0: "one"}`, true},
+		{`!("a" in ["b"])`, true},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		testBooleanObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestCollectionBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		// map
+		{"map([1, 2, 3], fn(x) { x * 2 })", "[2, 4, 6]"},
+		{"map([], fn(x) { x })", "[]"},
+		{"map([1], len)", "Error: argument to `len` not supported, got Integer"},
+		{"map(1, fn(x) { x })", "Error: first argument to `map` must be Array, got Integer"},
+		{"map([1], 1)", "Error: second argument to `map` must be Function, got Integer"},
+		{"map([1], fn(x, y) { x })", "Error: wrong number of arguments. want=2, got=1"},
+		// filter
+		{"filter([1, 2, 3, 4], fn(x) { x > 2 })", "[3, 4]"},
+		{"filter([1, 2], fn(x) { false })", "[]"},
+		// reduce
+		{"reduce([1, 2, 3], fn(acc, x) { acc + x }, 10)", "16"},
+		{"reduce([1, 2, 3], fn(acc, x) { acc * x })", "6"},
+		{"reduce([], fn(acc, x) { acc + x }, 0)", "0"},
+		{"reduce([], fn(acc, x) { acc + x })", "Error: `reduce` of empty Array with no initial value"},
+		{"reduce([1])", "Error: wrong number of arguments. want=2 or 3, got=1"},
+		// each
+		{"let sum = [0]; each([1, 2], fn(x) { push(sum, x) })", "nil"},
+		{`each([1, "a"], fn(x) { -x })`, "Error: unknown operator: -String"},
+		// sort
+		{"sort([3, 1, 2])", "[1, 2, 3]"},
+		{`sort(["b", "a", "c"])`, "[a, b, c]"},
+		{"sort([3, 1, 2], fn(a, b) { compare(b, a) })", "[3, 2, 1]"},
+		{`sort([[2, "a"], [1, "b"], [2, "c"], [1, "d"]], fn(a, b) { compare(a[0], b[0]) })`,
+			"[[1, b], [1, d], [2, a], [2, c]]"},
+		{"let a = [2, 1]; sort(a); a", "[2, 1]"},
+		{`sort([1, "a"])`, "Error: cannot compare String with Integer"},
+		{"sort([1, 2], fn(a, b) { true })", "Error: comparator of `sort` must return Integer, got Boolean"},
+		// reverse
+		{"reverse([1, 2, 3])", "[3, 2, 1]"},
+		{`reverse("héllo")`, "olléh"},
+		{"reverse(1)", "Error: argument to `reverse` not supported, got Integer"},
+		// zip
+		{`zip([1, 2, 3], ["a", "b"])`, "[[1, a], [2, b]]"},
+		{"zip([1], [2], [3])", "[[1, 2, 3]]"},
+		{"zip()", "Error: wrong number of arguments. want at least 1, got=0"},
+		{"zip([1], 2)", "Error: second argument to `zip` must be Array, got Integer"},
+		// flatten
+		{"flatten([1, [2, [3, [4]]], []])", "[1, 2, 3, 4]"},
+		{"flatten([1, [2, [3, [4]]]], 1)", "[1, 2, [3, [4]]]"},
+		{"flatten([[1]], 0)", "[[1]]"},
+		{"flatten([], -1)", "Error: second argument to `flatten` must be non-negative Integer, got -1"},
+		// uniq
+		{`uniq([1, 2, 1, "a", 1.0, "a", 3])`, "[1, 2, a, 3]"},
+		{"uniq([[1], [2], [1]])", "[[1], [2]]"},
+		// any, all and find
+		{"any([1, 2, 3], fn(x) { x > 2 })", "true"},
+		{"any([1, 2, 3], fn(x) { x > 3 })", "false"},
+		{"any([])", "false"},
+		{"any([false, 1])", "true"},
+		{"all([1, 2, 3], fn(x) { x > 0 })", "true"},
+		{"all([1, 2, 3], fn(x) { x > 1 })", "false"},
+		{"all([])", "true"},
+		{"find([1, 2, 3, 4], fn(x) { x > 2 })", "3"},
+		{"find([1, 2], fn(x) { x > 2 })", "nil"},
+		{"find([1, 2])", "Error: wrong number of arguments. want=2, got=1"},
+		// range
+		{"range(4)", "[0, 1, 2, 3]"},
+		{"range(2, 5)", "[2, 3, 4]"},
+		{"range(0, 10, 3)", "[0, 3, 6, 9]"},
+		{"range(5, 0, -2)", "[5, 3, 1]"},
+		{"range(0)", "[]"},
+		{"range(3, 1)", "[]"},
+		{"range(-9223372036854775807 - 1, -9223372036854775807 - 1 + 3)", "[-9223372036854775808, -9223372036854775807, -9223372036854775806]"},
+		{"range(9223372036854775806, 9223372036854775807, 5)", "[9223372036854775806]"},
+		{"range(-9223372036854775806, -9223372036854775807, -9223372036854775807 - 1)", "[-9223372036854775806]"},
+		{"range(9223372036854775805, 9223372036854775807, 9223372036854775807)", "[9223372036854775805]"},
+		{"range(0, 9223372036854775807)", "Error: range too large: 9223372036854775807 elements"},
+		{"range(-9223372036854775807 - 1, 9223372036854775807)", "Error: range too large: 18446744073709551615 elements"},
+		{"range(9223372036854775807, -9223372036854775807 - 1, -1)", "Error: range too large: 18446744073709551615 elements"},
+		{"range(0, 1, 0)", "Error: step of `range` must not be zero"},
+		{"range(1.5)", "Error: first argument to `range` must be Integer, got Float"},
+		{"range()", "Error: wrong number of arguments. want=1..3, got=0"},
+		// enumerate
+		{`enumerate(["a", "b"])`, "[[0, a], [1, b]]"},
+		{"enumerate([])", "[]"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		if got := evaluated.Inspect(); got != tt.expected {
+			t.Errorf("%s: expected %q, but got %q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestHashBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		// keys, values and items
+		{`keys({"b": 1, "a": 2, 3: 3})`, "[b, a, 3]"},
+		{`values({"b": 1, "a": 2, 3: 3})`, "[1, 2, 3]"},
+		{`items({"b": 1, true: [2]})`, "[[b, 1], [true, [2]]]"},
+		{"keys({})", "[]"},
+		{"keys([1])", "Error: first argument to `keys` must be Hash, got Array"},
+		{"values()", "Error: wrong number of arguments. want=1, got=0"},
+		// has
+		{`has({"a": [][0]}, "a")`, "true"},
+		{`has({"a": 1}, "b")`, "false"},
+		{`has({1: 1}, 1.0)`, "true"},
+		{`has({}, [1])`, "Error: unusable as hash key: Array"},
+		// get
+		{`get({"a": 1}, "a")`, "1"},
+		{`get({"a": 1}, "b")`, "nil"},
+		{`get({"a": 1}, "b", 0)`, "0"},
+		{`get({"a": [][0]}, "a", 0)`, "nil"},
+		{`get({}, fn() {}, 0)`, "Error: unusable as hash key: Function"},
+		// set
+		{`set({"a": 1, "b": 2}, "a", 3)`, "{a: 3, b: 2}"},
+		{`set({"a": 1}, "c", 3)`, "{a: 1, c: 3}"},
+		{`let h = {"a": 1}; set(h, "a", 2); h`, "{a: 1}"},
+		{`set({}, {}, 1)`, "Error: unusable as hash key: Hash"},
+		{`set({}, 1)`, "Error: wrong number of arguments. want=3, got=2"},
+		// delete
+		{`delete({"a": 1, "b": 2, "c": 3}, "b")`, "{a: 1, c: 3}"},
+		{`delete({"a": 1, "b": 2, "c": 3}, "a", "c", "z")`, "{b: 2}"},
+		{`let h = {"a": 1}; delete(h, "a"); h`, "{a: 1}"},
+		{`delete({"a": 1})`, "Error: wrong number of arguments. want at least 2, got=1"},
+		{`delete({"a": 1}, ["a"])`, "Error: unusable as hash key: Array"},
+		// merge
+		{`merge({"a": 1, "b": 2}, {"b": 3}, {"c": 4, "a": 5})`, "{a: 5, b: 3, c: 4}"},
+		{`merge({"a": 1})`, "{a: 1}"},
+		{`let h = {"a": 1}; merge(h, {"a": 2}); h`, "{a: 1}"},
+		{`merge({}, [])`, "Error: second argument to `merge` must be Hash, got Array"},
+		{"merge()", "Error: wrong number of arguments. want at least 1, got=0"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		if got := evaluated.Inspect(); got != tt.expected {
+			t.Errorf("%s: expected %q, but got %q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestStringBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		// split and join
+		{`split("a,b,,c", ",")`, []string{"a", "b", "", "c"}},
+		{"split(\"  a b\tc  \")", []string{"a", "b", "c"}},
+		{`split("héllo", "")`, []string{"h", "é", "l", "l", "o"}},
+		{`split("", ",")`, []string{""}},
+		{`split(1, ",")`, "first argument to `split` must be String, got Integer"},
+		{`join(["a", "b", "c"], ", ")`, "a, b, c"},
+		{`join(["a", "b"])`, "ab"},
+		{`join([], "-")`, ""},
+		{`join(["a", 1], "-")`, "elements of array passed to `join` must be String, got Integer"},
+		{`join("ab", "-")`, "first argument to `join` must be Array, got String"},
+		// trim, upper and lower
+		{"trim(\"  \thello\n \")", "hello"},
+		{`trim("xxhixyx", "xy")`, "hi"},
+		{`upper("éclair")`, "ÉCLAIR"},
+		{`lower("ÉCLAIR")`, "éclair"},
+		{`upper("a", "b")`, "wrong number of arguments. want=1, got=2"},
+		// replace
+		{`replace("a-b-c", "-", "+")`, "a+b+c"},
+		{`replace("a-b-c", "-", "+", 1)`, "a+b-c"},
+		{`replace("ab", "", "-")`, "-a-b-"},
+		{`replace(repeat("a", 5000), "", repeat("b", 50000))`, "result of `replace` too large"},
+		{`len(replace(repeat("a", 5000), "", repeat("b", 50000), 2))`, 105000},
+		{`replace("aaa", "a", "")`, ""},
+		{`replace("a-b", "-", 1)`, "third argument to `replace` must be String, got Integer"},
+		{`replace("a-b", "-", "+", "1")`, "fourth argument to `replace` must be Integer, got String"},
+		// contains, starts_with and ends_with
+		{`contains("hello", "ell")`, true},
+		{`contains("hello", "xyz")`, false},
+		{`starts_with("héllo", "hé")`, true},
+		{`starts_with("hello", "lo")`, false},
+		{`ends_with("hello", "lo")`, true},
+		{`ends_with("hello", "")`, true},
+		{`contains("hello", 1)`, "second argument to `contains` must be String, got Integer"},
+		// index_of
+		{`index_of("hello", "l")`, 2},
+		{`index_of("héllo", "llo")`, 2},
+		{`index_of("hello", "z")`, -1},
+		{`let s = "日本語です"; s[index_of(s, "語"):]`, "語です"},
+		// repeat
+		{`repeat("ab", 3)`, "ababab"},
+		{`repeat("ab", 0)`, ""},
+		{`repeat("ab", -1)`, "negative repeat count: -1"},
+		{`repeat("ab", 4611686018427387904)`, "repeat count too large: 4611686018427387904"},
+		{`repeat("", 9223372036854775807)`, ""},
+		{`repeat("ab", "3")`, "second argument to `repeat` must be Integer, got String"},
+		// pad_left and pad_right
+		{`pad_left("7", 3, "0")`, "007"},
+		{`pad_left("ab", 5)`, "   ab"},
+		{`pad_left("abcdef", 3)`, "abcdef"},
+		{`pad_right("é", 4, "ab")`, "éaba"},
+		{`pad_right("日本", 3, "・")`, "日本・"},
+		{`pad_right("a", 3, "")`, "third argument to `pad_right` must not be empty"},
+		{`pad_left("x", 9223372036854775807)`, "width of `pad_left` too large: 9223372036854775807"},
+		{`pad_right("x", 67108865, "ab")`, "width of `pad_right` too large: 67108865"},
+		{`pad_left("x", -9223372036854775807)`, "x"},
+		// chars and lines
+		{`chars("héllo")`, []string{"h", "é", "l", "l", "o"}},
+		{`chars("")`, []string{}},
+		{"lines(\"a\nb\r\nc\n\")", []string{"a", "b", "c"}},
+		{"lines(\"a\n\nb\")", []string{"a", "", "b"}},
+		{"lines(\"\n\")", []string{""}},
+		{`lines("")`, []string{}},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case string:
+			if errObj, ok := evaluated.(*object.Error); ok {
+				if errObj.Message != expected {
+					t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+				}
+				continue
+			}
+			testStringObject(t, evaluated, expected)
+		case []string:
+			arr, ok := evaluated.(*object.Array)
+			if !ok {
+				t.Errorf("object is not *object.Array. got=%#v", evaluated)
+				continue
+			}
+			if len(arr.Elements) != len(expected) {
+				t.Errorf("wrong number of elements for %s. want=%d, got=%d",
+					tt.input, len(expected), len(arr.Elements))
+				continue
+			}
+			for i, elem := range arr.Elements {
+				testStringObject(t, elem, expected[i])
+			}
+		}
+	}
+}
+
+func TestTypeBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		// type
+		{"type(1)", "Integer"},
+		{"type(1.5)", "Float"},
+		{`type("a")`, "String"},
+		{"type(true)", "Boolean"},
+		{"type([][0])", "Nil"},
+		{"type([])", "Array"},
+		{"type({})", "Hash"},
+		{"type(fn() {})", "Function"},
+		{"type(len)", "Builtin"},
+		{"type()", "Error: wrong number of arguments. want=1, got=0"},
+		// int
+		{"int(42)", "42"},
+		{"int(3.9)", "3"},
+		{"int(-3.9)", "-3"},
+		{`int("-12")`, "-12"},
+		{`int("+7")`, "7"},
+		{"int(true)", "1"},
+		{`int("1.5")`, `Error: could not parse "1.5" as Integer`},
+		{`int(" 1")`, `Error: could not parse " 1" as Integer`},
+		{`int("0x10")`, `Error: could not parse "0x10" as Integer`},
+		{`int("99999999999999999999")`, `Error: could not parse "99999999999999999999" as Integer`},
+		{`int(float("1e19"))`, "Error: cannot convert 10000000000000000000 to Integer"},
+		{"int([])", "Error: argument to `int` not supported, got Array"},
+		// float
+		{"float(2)", "2"},
+		{"type(float(2))", "Float"},
+		{`float("1.5")`, "1.5"},
+		{`float("-2e3")`, "-2000"},
+		{`float("abc")`, `Error: could not parse "abc" as Float`},
+		{`float("NaN")`, `Error: could not parse "NaN" as Float`},
+		{`float("1e400")`, `Error: could not parse "1e400" as Float`},
+		{`float("0x1p-2")`, `Error: could not parse "0x1p-2" as Float`},
+		{`float("1_000.5")`, `Error: could not parse "1_000.5" as Float`},
+		{`float("Inf")`, `Error: could not parse "Inf" as Float`},
+		{`float(" 1.5")`, `Error: could not parse " 1.5" as Float`},
+		{"float(\"1.5\n\")", `Error: could not parse "1.5\n" as Float`},
+		{`float("")`, `Error: could not parse "" as Float`},
+		{`float(".")`, `Error: could not parse "." as Float`},
+		{`float("1e")`, `Error: could not parse "1e" as Float`},
+		{`float("+.5")`, "0.5"},
+		{`float("5.")`, "5"},
+		{`float("1E-2")`, "0.01"},
+		{"float(true)", "Error: argument to `float` not supported, got Boolean"},
+		// str
+		{"str(12)", "12"},
+		{"str(1.5)", "1.5"},
+		{`str("a")`, "a"},
+		{`str([1, "a"])`, "[1, a]"},
+		{`str(12) + "px"`, "12px"},
+		// bool
+		{"bool(true)", "true"},
+		{"bool(0)", "false"},
+		{"bool(2.5)", "true"},
+		{"bool([][0])", "false"},
+		{`bool("false")`, "false"},
+		{`bool("yes")`, `Error: could not parse "yes" as Boolean`},
+		{"bool([])", "Error: argument to `bool` not supported, got Array"},
+		// predicates
+		{"is_int(1)", "true"},
+		{`is_int("1")`, "false"},
+		{"is_float(1.0)", "true"},
+		{"is_number(1)", "true"},
+		{"is_number(1.5)", "true"},
+		{`is_number("1")`, "false"},
+		{`is_string("1")`, "true"},
+		{"is_bool(false)", "true"},
+		{"is_nil([][0])", "true"},
+		{"is_nil(0)", "false"},
+		{"is_array([])", "true"},
+		{"is_hash({})", "true"},
+		{"is_function(fn() {})", "true"},
+		{"is_function(len)", "true"},
+		{"is_function(1)", "false"},
+		{"is_int(1, 2)", "Error: wrong number of arguments. want=1, got=2"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		if got := evaluated.Inspect(); got != tt.expected {
+			t.Errorf("%s: expected %q, but got %q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestOutputBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`puts(1, "a", [true])`, "1\na\n[true]\n"},
+		{"puts()", ""},
+		{`print("a", 1); print("b")`, "a 1b"},
+		{"printf(\"%d-%s\n\", 1, \"a\")", "1-a\n"},
+		{"let f = fn(x) { fn() { puts(x) } }; f(2)()", "2\n"},
+		{"each([1, 2], puts)", "1\n2\n"},
+		{"let p = puts; p(3)", "3\n"},
+	}
+
+	for _, tt := range tests {
+		program := parser.New(lexer.New(tt.input)).ParseProgram()
+		var out bytes.Buffer
+		evaluated := Eval(program, object.NewEnvironment(object.WithOutput(&out)))
+
+		if isError(evaluated) {
+			t.Errorf("%s: unexpected error: %s", tt.input, evaluated.Inspect())
+			continue
+		}
+		if got := out.String(); got != tt.expected {
+			t.Errorf("%s: expected output %q, but got %q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestSprintf(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`sprintf("plain")`, "plain"},
+		{`sprintf("%d apples", 3)`, "3 apples"},
+		{`sprintf("[%5d|%-5d|%05d|%+d]", 42, 42, 42, 42)`, "[   42|42   |00042|+42]"},
+		{`sprintf("%f", 1.5)`, "1.500000"},
+		{`sprintf("%.2f", 3.14159)`, "3.14"},
+		{`sprintf("%8.3f|", 2)`, "   2.000|"},
+		{`sprintf("%s and %s", "this", "that")`, "this and that"},
+		{`sprintf("[%6s|%-6s]", "héllo", "日本")`, "[ héllo|日本    ]"},
+		{`sprintf("%.3s", "héllo")`, "hél"},
+		{"sprintf(\"%q\", \"tab\there\")", `"tab\there"`},
+		{`sprintf("%v %v %v", [1, "a"], {"k": true}, 1.5)`, "[1, a] {k: true} 1.5"},
+		{`sprintf("%v", "a")`, "a"},
+		{`sprintf("100%%")`, "100%"},
+		{`sprintf("%d", "1")`, "Error: format verb %d needs Integer, got String"},
+		{`sprintf("%f", "1")`, "Error: format verb %f needs Integer or Float, got String"},
+		{`sprintf("%5s", 1)`, "Error: format verb %5s needs String, got Integer"},
+		{`sprintf("%d %d", 1)`, "Error: missing argument for format verb %d"},
+		{`sprintf("%d", 1, 2)`, "Error: too many arguments for format. want=1, got=2"},
+		{`sprintf("%x", 1)`, "Error: unknown format verb %x"},
+		{`sprintf("50%")`, "Error: incomplete format verb % at end of format"},
+		{`len(sprintf("%10000d", 1))`, "10000"},
+		{`sprintf("%99999999d", 1)`, "Error: width or precision of format verb %99999999d too large"},
+		{`sprintf("%.10001f", 1.5)`, "Error: width or precision of format verb %.10001f too large"},
+		{`sprintf("%-099999999999999999999s", "a")`, "Error: width or precision of format verb %-099999999999999999999s too large"},
+		{`sprintf(1)`, "Error: first argument to `sprintf` must be String, got Integer"},
+		{`sprintf()`, "Error: wrong number of arguments. want at least 1, got=0"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		if got := evaluated.Inspect(); got != tt.expected {
+			t.Errorf("%s: expected %q, but got %q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestMathBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		// constants
+		{"PI", "3.141592653589793"},
+		{"E", "2.718281828459045"},
+		{"INF", "Inf"},
+		{"-INF", "-Inf"},
+		{"NAN", "NaN"},
+		{"let PI = 3; PI", "3"},
+		{"1.0 / 0", "Inf"},
+		// abs, floor, ceil and round
+		{"abs(-3)", "3"},
+		{"type(abs(-3))", "Integer"},
+		{"abs(-2.5)", "2.5"},
+		{"abs(-INF)", "Inf"},
+		{"floor(2.7)", "2"},
+		{"type(floor(2.7))", "Float"},
+		{"floor(-2.5)", "-3"},
+		{"floor(7)", "7"},
+		{"ceil(2.1)", "3"},
+		{"round(2.5)", "3"},
+		{"round(-2.5)", "-3"},
+		{"round(3.14159, 2)", "3.14"},
+		{"round(1234, -2)", "1200"},
+		{"type(round(1234, -2))", "Integer"},
+		{"round(NAN)", "NaN"},
+		{"round(1.5, 400)", "1.5"},
+		{"round(pow(10, 300.0) + 0.5, 100) == pow(10, 300.0)", "true"},
+		{"round(1.5, -400)", "0"},
+		{"round(1234, -400)", "0"},
+		{"round(1234567890123456789, -1)", "1234567890123456790"},
+		{"round(-1234567890123456789, -1)", "-1234567890123456790"},
+		{"round(9223372036854775807, -1)", "9223372036854776000"},
+		{"type(round(9223372036854775807, -1))", "Float"},
+		{"round(9223372036854775807, -18)", "9000000000000000000"},
+		{"round(-9223372036854775807 - 1, -2)", "-9223372036854775800"},
+		{"round(1250, -2)", "1300"},
+		{"round(-1250, -2)", "-1300"},
+		{"round(1249, -2)", "1200"},
+		{"round(4999999999999999999, -19)", "0"},
+		{"round(5000000000000000000, -19)", "10000000000000000000"},
+		{"round(-5000000000000000000, -19)", "-10000000000000000000"},
+		{"type(round(-5000000000000000000, -19))", "Float"},
+		{"round(9223372036854775807, -20)", "0"},
+		{"type(round(1234, -400))", "Integer"},
+		{"is_inf(round(INF, -400))", "true"},
+		{"round(NAN, 400)", "NaN"},
+		{`floor("1")`, "Error: argument to `floor` must be Integer or Float, got String"},
+		// min and max
+		{"min(3, 1, 2)", "1"},
+		{"max(3, 1, 2)", "3"},
+		{"max([1, 5, 2])", "5"},
+		{"min(1, 2.5)", "1"},
+		{"type(min(1, 2.5))", "Float"},
+		{"max(1, NAN, 2)", "NaN"},
+		{"max(-INF, 1)", "1"},
+		{"max(7)", "7"},
+		{"min([])", "Error: `min` needs at least 1 number"},
+		{`max(1, "2")`, "Error: arguments to `max` must be Integer or Float, got String"},
+		// pow and sqrt
+		{"pow(2, 10)", "1024"},
+		{"type(pow(2, 10))", "Integer"},
+		{"pow(-3, 3)", "-27"},
+		{"pow(5, 0)", "1"},
+		{"pow(2, 62)", "4611686018427387904"},
+		{"pow(-2, 63)", "-9223372036854775808"},
+		{"pow(2, 64)", "18446744073709552000"},
+		{"type(pow(2, 64))", "Float"},
+		{"pow(-3, 41)", "-36472996377170790000"},
+		{"pow(1, 9223372036854775807)", "1"},
+		{"pow(-1, 9223372036854775807)", "-1"},
+		{"pow(2, -1)", "0.5"},
+		{"pow(2.5, 2)", "6.25"},
+		{"pow(4, 0.5)", "2"},
+		{"pow(-8, 1.0 / 3)", "NaN"},
+		{"sqrt(16)", "4"},
+		{"type(sqrt(16))", "Float"},
+		{"sqrt(-1)", "NaN"},
+		{`sqrt("4")`, "Error: first argument to `sqrt` must be Integer or Float, got String"},
+		// exp and log
+		{"exp(0)", "1"},
+		{"log(E)", "1"},
+		{"log(0)", "-Inf"},
+		{"log2(8)", "3"},
+		{"log10(1000)", "3"},
+		// trigonometric functions
+		{"sin(0)", "0"},
+		{"cos(0)", "1"},
+		{"round(sin(PI / 2), 6)", "1"},
+		{"round(tan(PI / 4), 6)", "1"},
+		{"round(asin(1) * 2, 6) == round(PI, 6)", "true"},
+		{"acos(1)", "0"},
+		{"atan(0)", "0"},
+		{"atan2(1, 1) == PI / 4", "true"},
+		{"sin()", "Error: wrong number of arguments. want=1, got=0"},
+		// is_nan and is_inf
+		{"is_nan(NAN)", "true"},
+		{"is_nan(sqrt(-1))", "true"},
+		{"is_nan(1)", "false"},
+		{"is_inf(-INF)", "true"},
+		{"is_inf(pow(10, 308.0) * 10)", "true"},
+		{"is_inf(1.5)", "false"},
+		{"NAN == NAN", "false"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		if got := evaluated.Inspect(); got != tt.expected {
+			t.Errorf("%s: expected %q, but got %q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestJSONParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`null`, "nil"},
+		{`true`, "true"},
+		{`42`, "42"},
+		{`-7`, "-7"},
+		{`1.5`, "1.5"},
+		{`1e3`, "1000"},
+		{`12345678901234567890`, "12345678901234567000"},
+		{`"h\u00e9llo\n"`, "héllo\n"},
+		{`[1, "a", [true, null], {}]`, "[1, a, [true, nil], {}]"},
+		{`{"b": 1, "a": {"c": [2.5]}, "d": "x"}`, "{b: 1, a: {c: [2.5]}, d: x}"},
+		{`{"a": 1, "b": 2, "a": 3}`, "{a: 3, b: 2}"},
+		{` [ ] `, "[]"},
+		{``, "Error: could not parse JSON: unexpected end of JSON input"},
+		{`[1, 2`, "Error: could not parse JSON: unexpected end of JSON input"},
+		{`{"a" 1}`, "Error: could not parse JSON: invalid character '1' after object key"},
+		{`[1] [2]`, "Error: could not parse JSON: invalid character '[' after top-level value"},
+		{`nul`, "Error: could not parse JSON: unexpected end of JSON input"},
+		{`[1,]`, "Error: could not parse JSON: invalid character ']' looking for beginning of value"},
+		{`1e400`, "Error: could not parse JSON: number 1e400 out of range"},
+	}
+
+	for _, tt := range tests {
+		program := parser.New(lexer.New("json_parse(input)")).ParseProgram()
+		env := object.NewEnvironment(object.WithOutput(ioutil.Discard))
+		env.Set("input", &object.String{Value: tt.input})
+
+		evaluated := Eval(program, env)
+		if got := evaluated.Inspect(); got != tt.expected {
+			t.Errorf("%s: expected %q, but got %q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestJSONStringify(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"json_stringify([][0])", "null"},
+		{"json_stringify(false)", "false"},
+		{"json_stringify(-12)", "-12"},
+		{"json_stringify(0.5)", "0.5"},
+		{"json_stringify(2.0)", "2.0"},
+		{`json_stringify("héllo <b>")`, `"héllo <b>"`},
+		{"json_stringify(\"tab\there\")", `"tab\there"`},
+		{`json_stringify({"b": [1, 2.5], "a": {}, "c": []})`, `{"b":[1,2.5],"a":{},"c":[]}`},
+		{`json_stringify({"a": [1, {"b": true}]}, 2)`, "{\n  \"a\": [\n    1,\n    {\n      \"b\": true\n    }\n  ]\n}"},
+		{`json_stringify([1], "	")`, "[\n\t1\n]"},
+		{`json_stringify([1], 0)`, "[1]"},
+		{`json_parse(json_stringify({"x": [1, 2.0, "s", [][0]]}))`, "{x: [1, 2, s, nil]}"},
+		{`type(json_parse(json_stringify(2.0)))`, "Float"},
+		{`json_stringify(fn(x) { x })`, "Error: cannot convert Function to JSON"},
+		{`json_stringify([len])`, "Error: cannot convert Builtin to JSON"},
+		{`json_stringify({1: "one"})`, "Error: cannot convert hash key of Integer to JSON"},
+		{`json_stringify(NAN)`, "Error: cannot convert NaN to JSON"},
+		{`json_stringify({"a": [INF]})`, "Error: cannot convert Inf to JSON"},
+		{`json_stringify(1, -1)`, "Error: indent of `json_stringify` must not be negative, got -1"},
+		{`json_stringify([1], 10)`, "[\n          1\n]"},
+		{`json_stringify([1], 11)`, "Error: indent of `json_stringify` must be at most 10, got 11"},
+		{`json_stringify([1], "··········")`, "[\n··········1\n]"},
+		{`json_stringify([1], "-----------")`, "Error: indent of `json_stringify` must be at most 10 characters, got 11"},
+		{`json_stringify([1], 9223372036854775807)`, "Error: indent of `json_stringify` must be at most 10, got 9223372036854775807"},
+		{`json_stringify(1, true)`, "Error: second argument to `json_stringify` must be Integer or String, got Boolean"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		if got := evaluated.Inspect(); got != tt.expected {
+			t.Errorf("%s: expected %q, but got %q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestMemberExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let cfg = {"db": {"host": "localhost", "port": 5432}}; cfg.db.host`, "localhost"},
+		{`let cfg = {"db": {"port": 5432}}; cfg.db.port + 1`, "5433"},
+		{`{"a": 1}.b`, "nil"},
+		{`{"a": [1, 2]}.a[1]`, "2"},
+		{`{"f": fn(x) { x * 10 }}.f(2)`, "20"},
+		{`let obj = {"name": "monkey"}; obj.keys()`, "[name]"},
+		{`let keys = fn(h) { "own" }; {}.keys()`, "own"},
+		{"[3, 1, 2].sort().map(fn(x) { x * 2 })", "[2, 4, 6]"},
+		{`"a,b".split(",").join("-")`, "a-b"},
+		{"5.abs() + (-5).abs()", "10"},
+		{"let add = fn(a, b) { a + b }; 1.add(2)", "3"},
+		{`"hi".upper().len()`, "2"},
+		{"[1].push(2, 3)", "Error: wrong number of arguments. want=2, got=3"},
+		{"1.nothing()", "Error: identifier not found: nothing"},
+		{`{"f": 1}.f()`, "Error: not a function: Integer"},
+		{"1.x", "Error: member access not supported: Integer"},
+		{"[1].len", "Error: member access not supported: Array"},
+		{"x.y", "Error: identifier not found: x"},
+		{"[1].map(y)", "Error: identifier not found: y"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		if got := evaluated.Inspect(); got != tt.expected {
+			t.Errorf("%s: expected %q, but got %q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestExceptions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`throw "boom"`, "Error: boom"},
+		{`throw {"type": "ValueError", "message": "bad value"}`, "ValueError: bad value"},
+		{"throw 42", "Error: 42"},
+		{`throw {"type": 1}`, "Error: error type must be String, got Integer"},
+		{"throw x", "Error: identifier not found: x"},
+		{`try { throw "boom" } catch (e) { e.message }`, "boom"},
+		{`try { throw "boom" } catch (e) { e.type }`, "Error"},
+		{`try { throw [1, 2] } catch (e) { e.value }`, "[1, 2]"},
+		{`try { throw {"message": "m", "code": 3} } catch (e) { [e.message, e.value.code] }`, "[m, 3]"},
+		{`try { throw "boom" } catch { "caught" }`, "caught"},
+		{"try { 1 + 1 } catch (e) { 0 }", "2"},
+		{"try { len(1, 2) } catch (e) { [e.type, e.message] }",
+			"[RuntimeError, wrong number of arguments. want=1, got=2]"},
+		{"try { undefined } catch (e) { e.message }", "identifier not found: undefined"},
+		{"try { 1 + true } catch (e) { e.value }", "nil"},
+		{`try { try { throw "inner" } catch (e) { throw e } } catch (e) { e.message }`, "inner"},
+		{`try { try { throw "inner" } finally { 1 } } catch (e) { e.message }`, "inner"},
+		{`try { throw "a" } catch (e) { throw "b" }`, "Error: b"},
+		{`try { throw "a" } catch (e) { 1 } finally { throw "c" }`, "Error: c"},
+		{`let f = fn() { let r = try { throw "x" } catch (e) { 1 } finally { return "done" }; r }; f()`, "done"},
+		{`let f = fn() { try { return 1 } finally { 2 }; 3 }; f()`, "1"},
+		{`let f = fn() { try { return 1 } finally { return 2 } }; f()`, "2"},
+		{`let f = fn() { try { throw "x" } catch (e) { return e.message }; "unreachable" }; f()`, "x"},
+		{`let e = "outer"; try { throw "x" } catch (e) { e }; e`, "outer"},
+		{`let div = fn(a, b) { if (b == 0) { throw "division by zero" }; a / b };
+		let calc = fn(x) { div(x, 0) };
+		try { calc(1) } catch (e) { e.stack }`, "[div(x, 0), calc(1)]"},
+		{`try { [1].map(fn(x) { throw "m" }) } catch (e) { e.stack }`,
+			"[([1].map)(fn(x) throw m;)]"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		if got := evaluated.Inspect(); got != tt.expected {
+			t.Errorf("%s: expected %q, but got %q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestMatchExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`match (1) { 1 => "one", 2 => "two" }`, "one"},
+		{`match (2) { 1 => "one", 2 => "two" }`, "two"},
+		{`match (2.0) { 2 => "int", _ => "other" }`, "int"},
+		{`match (-1) { -1 => "minus one" }`, "minus one"},
+		{`match ("b") { "a" => 1, "b" => 2 }`, "2"},
+		{`match (false) { true => 1, false => 0 }`, "0"},
+		{`match (1) { true => "bool", _ => "other" }`, "other"},
+		{"match (5) { n => n * 2 }", "10"},
+		{"match (5) { n if n > 10 => \"big\", n if n > 0 => \"small\", _ => \"none\" }", "small"},
+		{"match ([]) { [] => 0, [a] => a }", "0"},
+		{"match ([1, 2]) { [a] => a, [a, b] => a + b }", "3"},
+		{"match ([1, 2, 3]) { [h, ..t] => [h, t] }", "[1, [2, 3]]"},
+		{"match ([1]) { [h, ..t] => [h, t] }", "[1, []]"},
+		{"match ([]) { [h, .._] => h, [.._] => \"empty\" }", "empty"},
+		{"match ([[1, 2], 3]) { [[a, b], c] => a + b + c }", "6"},
+		{"match ([1, 2]) { [1, x] => x, _ => 0 }", "2"},
+		{"match ([3, 2]) { [1, x] => x, _ => 0 }", "0"},
+		{"match ([1, 2]) { [a, a] => a }", "2"},
+		{`match ({"x": 1, "y": 2}) { {"x": x, "y": y} => x + y }`, "3"},
+		{`match ({"x": 1}) { {"x": x, "y": y} => 0, {"x": x} => x }`, "1"},
+		{`match ({"name": "monkey", "age": 3}) { {name} => name }`, "monkey"},
+		{`match ({"kind": "circle", "r": 2}) { {"kind": "square"} => 0, {"kind": "circle", "r": r} => r * r }`, "4"},
+		{`match ({1: "one", true: "yes"}) { {1: a, true: b} => a + b }`, "oneyes"},
+		{`match ([1]) { {"a": a} => a, [a] => a }`, "1"},
+		{`match ({}) { [] => 1, {} => 2 }`, "2"},
+		{"let x = 10; match (1) { x => x }; x", "10"},
+		{"let f = fn(xs) { match (xs) { [] => 0, [h, ..t] => h + f(t) } }; f([1, 2, 3, 4])", "10"},
+		{`let n = match (3) { 1 => "a", _ => "b" }; n`, "b"},
+		{"match (3) { 1 => 2 }", "Error: no match arm matches 3"},
+		{"match ([1, 2]) { [a] => a }", "Error: no match arm matches [1, 2]"},
+		{"match (x) { _ => 1 }", "Error: identifier not found: x"},
+		{"match (1) { n if n + true => 1 }", "Error: type mismatch: Integer + Boolean"},
+		{"match (1) { 1 => 1 + true }", "Error: type mismatch: Integer + Boolean"},
+		{`try { match (1) { 2 => 2 } } catch (e) { e.message }`, "no match arm matches 1"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		if got := evaluated.Inspect(); got != tt.expected {
+			t.Errorf("%s: expected %q, but got %q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestDestructuring(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let [a, b] = [1, 2]; a + b", "3"},
+		{"let [a, b, ..rest] = [1, 2, 3, 4]; [a, b, rest]", "[1, 2, [3, 4]]"},
+		{"let [a, ..rest] = [1]; rest", "[]"},
+		{"let [_, b, .._] = [1, 2, 3]; b", "2"},
+		{"let [[a, b], c] = [[1, 2], 3]; a + b + c", "6"},
+		{"let [] = []; 1", "1"},
+		{`let {name, age: years} = {"name": "monkey", "age": 3}; [name, years]`, "[monkey, 3]"},
+		{`let {"first name": first, 1: one} = {"first name": "a", 1: "b"}; first + one`, "ab"},
+		{`let {pos: [x, y]} = {"pos": [1, 2], "extra": true}; x * 10 + y`, "12"},
+		{`let {a} = {"a": [][0]}; a`, "nil"},
+		{"let [x, 1] = [2, 1]; x", "2"},
+		{"let f = fn([a, b]) { a * b }; f([3, 4])", "12"},
+		{`let greet = fn({name}, greeting) { greeting + ", " + name }; greet({"name": "monkey"}, "hi")`, "hi, monkey"},
+		{"let f = fn([h, ..t]) { t }; f([1, 2, 3])", "[2, 3]"},
+		{"let sum = fn(xs) { if (len(xs) == 0) { return 0 }; let [h, ..t] = xs; h + sum(t) }; sum([1, 2, 3])", "6"},
+		{`[[1, 2], [3, 4]].map(fn([a, b]) { a + b })`, "[3, 7]"},
+		{"let [a, b] = [1, 2, 3]", "Error: cannot destructure into [a, b]: want 2 elements, got 3"},
+		{"let [a, b, ..c] = [1]", "Error: cannot destructure into [a, b, ..c]: want at least 2 elements, got 1"},
+		{"let [a] = 1", "Error: cannot destructure into [a]: want Array, got Integer"},
+		{`let {a} = [1]`, "Error: cannot destructure into {a: a}: want Hash, got Array"},
+		{`let {a, b} = {"a": 1}`, "Error: cannot destructure into {a: a, b: b}: missing key b"},
+		{`let {a: [b]} = {"a": 1}`, "Error: cannot destructure into {a: [b]}: want Array, got Integer"},
+		{"let [x, 1] = [2, 3]", "Error: cannot destructure into [x, 1]: want 1, got 3"},
+		{"let a = 0; let [a, b] = [1]; a", "Error: cannot destructure into [a, b]: want 2 elements, got 1"},
+		{"let a = 0; try { let [a, b] = [1] } catch (e) { 0 }; a", "0"},
+		{"let f = fn([a, b]) { a }; f(1)", "Error: cannot destructure into [a, b]: want Array, got Integer"},
+		{`let f = fn({name}) { name }; f({})`, "Error: cannot destructure into {name: name}: missing key name"},
+		{"let [a] = x", "Error: identifier not found: x"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		if got := evaluated.Inspect(); got != tt.expected {
+			t.Errorf("%s: expected %q, but got %q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestRestParametersAndSpread(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let f = fn(a, ...rest) { [a, rest] }; f(1)", "[1, []]"},
+		{"let f = fn(a, ...rest) { [a, rest] }; f(1, 2, 3)", "[1, [2, 3]]"},
+		{"let f = fn(...args) { len(args) }; f()", "0"},
+		{"let f = fn([a, b], ...rest) { a + b + len(rest) }; f([1, 2], 3, 4)", "5"},
+		{"let sum = fn(...xs) { reduce(xs, fn(acc, x) { acc + x }, 0) }; sum(1, 2, 3)", "6"},
+		{"fn(a, ...rest) { rest }", "fn(a, ...rest) {\nrest\n}"},
+		{"let f = fn(a, b) { a - b }; f(...[5, 3])", "2"},
+		{"let f = fn(...xs) { xs }; f(0, ...[1, 2], 3, ...[])", "[0, 1, 2, 3]"},
+		{"max(...[3, 9, 2])", "9"},
+		{"let xs = [2, 3]; [1, ...xs, ...[4, 5]]", "[1, 2, 3, 4, 5]"},
+		{"[...[]]", "[]"},
+		{"[1, 2].push(...[3])", "[1, 2, 3]"},
+		{`{"a": 1, ...{"b": 2, "a": 9}, "c": 3}`, "{a: 9, b: 2, c: 3}"},
+		{`let h = {"a": 1}; {...h, "a": 2}`, "{a: 2}"},
+		{`let h = {"a": 1}; {...h, "b": 2}; h`, "{a: 1}"},
+		{"{...{}}", "{}"},
+		{"let f = fn(a, ...rest) { a }; f()", "Error: wrong number of arguments. want at least 1, got=0"},
+		{"let f = fn(a) { a }; f(...[1, 2])", "Error: wrong number of arguments. want=1, got=2"},
+		{"let f = fn(a) { a }; f(...1)", "Error: cannot spread Integer, want Array"},
+		{`[..."ab"]`, "Error: cannot spread String, want Array"},
+		{"{...[1]}", "Error: cannot spread Array, want Hash"},
+		{"[...xs]", "Error: identifier not found: xs"},
+		{"{...h}", "Error: identifier not found: h"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		if got := evaluated.Inspect(); got != tt.expected {
+			t.Errorf("%s: expected %q, but got %q", tt.input, tt.expected, got)
+		}
+	}
+}
+
 func TestHashLiterals(t *testing.T) {
 	input := `
 	let two = "two";
@@ -492,26 +1724,38 @@ func TestHashLiterals(t *testing.T) {
 		t.Fatalf("object is not *object.Hash. got=%#v", evaluated)
 	}
 
-	expected := map[object.HashKey]int64{
-		(&object.String{Value: "one"}).HashKey():   1,
-		(&object.String{Value: "two"}).HashKey():   2,
-		(&object.String{Value: "three"}).HashKey(): 3,
-		(&object.Integer{Value: 4}).HashKey():      4,
-		TrueValue.HashKey():                        5,
-		FalseValue.HashKey():                       6,
+	expected := []struct {
+		key   object.Hashable
+		value int64
+	}{
+		{&object.String{Value: "one"}, 1},
+		{&object.String{Value: "two"}, 2},
+		{&object.String{Value: "three"}, 3},
+		{&object.Integer{Value: 4}, 4},
+		{TrueValue, 5},
+		{FalseValue, 6},
 	}
 
-	if l := len(hash.Pairs); l != len(expected) {
+	if l := hash.Len(); l != len(expected) {
 		t.Fatalf("hash has wrong number of pairs. want=%d, got=%d", len(expected), l)
 	}
 
-	for key, value := range expected {
-		pair, ok := hash.Pairs[key]
+	for i, pair := range hash.Pairs() {
+		if got, want := pair.Key.Inspect(), expected[i].key.Inspect(); got != want {
+			t.Errorf("pair %d has wrong key. want=%s, got=%s", i, want, got)
+		}
+
+		value, ok := hash.Get(expected[i].key)
 		if !ok {
-			t.Errorf("no pair for given key in Pairs: %#v", key)
+			t.Errorf("no pair for given key in Pairs: %#v", expected[i].key)
 			continue
 		}
-		testIntegerObject(t, pair.Value, value)
+		testIntegerObject(t, value, expected[i].value)
+	}
+
+	want := "{one: 1, two: 2, three: 3, 4: 4, true: 5, false: 6}"
+	if got := hash.Inspect(); got != want {
+		t.Errorf("hash.Inspect() wrong. want=%q, got=%q", want, got)
 	}
 }
 
@@ -527,6 +1771,9 @@ func TestHashIndexExpressions(t *testing.T) {
 		{`{5: 5}[5]`, 5},
 		{`{true: 5}[true]`, 5},
 		{`{false: 5}[false]`, 5},
+		{`{1: 5}[1.0]`, 5},
+		{`{2.0: 5}[2]`, 5},
+		{`{1.5: 5}[1.5]`, 5},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,107 @@
+package eval
+
+import (
+	"github.com/skatsuta/monkey-interpreter/ast"
+	"github.com/skatsuta/monkey-interpreter/object"
+)
+
+const (
+	// RuntimeErrorKind is the type of errors raised by the interpreter itself, e.g. a wrong number
+	// of arguments or an unknown identifier.
+	RuntimeErrorKind = "RuntimeError"
+	// ThrownErrorKind is the default type of errors thrown by `throw`.
+	ThrownErrorKind = "Error"
+)
+
+// evalThrowStatement evaluates `throw value`. A string becomes the message of the error, and a hash
+// may give its message and type in the keys "message" and "type", so that a caught error can be
+// thrown again. Any other value is described by its string representation.
+func evalThrowStatement(node *ast.ThrowStatement, env object.Environment) object.Object {
+	value := Eval(node.Value, env)
+	if isError(value) {
+		return value
+	}
+
+	thrown := &object.Error{
+		Message: value.Inspect(),
+		Kind:    ThrownErrorKind,
+		Value:   value,
+	}
+
+	if hash, ok := value.(*object.Hash); ok {
+		if msg, ok := hash.Get(&object.String{Value: "message"}); ok {
+			thrown.Message = msg.Inspect()
+		}
+		if kind, ok := hash.Get(&object.String{Value: "type"}); ok {
+			str, ok := kind.(*object.String)
+			if !ok {
+				return newError("error type must be String, got %s", kind.Type())
+			}
+			thrown.Kind = str.Value
+		}
+	}
+
+	return thrown
+}
+
+// evalTryExpression evaluates a try expression. An error in the try block is handed to the catch
+// block, if any, as a hash describing the error. The finally block always runs afterwards and its
+// value is discarded, unless it returns or fails itself.
+func evalTryExpression(node *ast.TryExpression, env object.Environment) object.Object {
+	result := Eval(node.Block, env)
+
+	if err, ok := result.(*object.Error); ok && node.Catch != nil {
+		catchEnv := object.NewEnclosedEnvironment(env)
+		if node.CatchParam != nil {
+			if bindErr := bind(node.CatchParam, errorToHash(err), catchEnv, false); bindErr != nil {
+				return bindErr
+			}
+		}
+		result = Eval(node.Catch, catchEnv)
+	}
+
+	if node.Finally != nil {
+		finally := Eval(node.Finally, env)
+		if isError(finally) {
+			return finally
+		}
+		if _, ok := finally.(*object.ReturnValue); ok {
+			return finally
+		}
+	}
+
+	return result
+}
+
+// errorToHash converts `err` into the hash bound to the parameter of a catch block.
+func errorToHash(err *object.Error) *object.Hash {
+	kind := err.Kind
+	if kind == "" {
+		kind = RuntimeErrorKind
+	}
+
+	value := err.Value
+	if value == nil {
+		value = NilValue
+	}
+
+	stack := make([]object.Object, 0, len(err.Stack))
+	for _, frame := range err.Stack {
+		stack = append(stack, &object.String{Value: frame})
+	}
+
+	hash := object.NewHash(4)
+	hash.Set(&object.String{Value: "message"}, &object.String{Value: err.Message})
+	hash.Set(&object.String{Value: "type"}, &object.String{Value: kind})
+	hash.Set(&object.String{Value: "value"}, value)
+	hash.Set(&object.String{Value: "stack"}, &object.Array{Elements: stack})
+	return hash
+}
+
+// withFrame records `call` in the stack of `result` if it is an error, and returns `result`.
+func withFrame(result object.Object, call *ast.CallExpression) object.Object {
+	if err, ok := result.(*object.Error); ok {
+		err.Stack = append(err.Stack, call.String())
+	}
+	return result
+}
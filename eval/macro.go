@@ -1,6 +1,8 @@
 package eval
 
 import (
+	"fmt"
+
 	"github.com/skatsuta/monkey-interpreter/ast"
 	"github.com/skatsuta/monkey-interpreter/object"
 )
@@ -30,6 +32,10 @@ func isMacroDefinition(node ast.Statement) bool {
 		return false
 	}
 
+	if _, ok := letStmt.Name.(*ast.Ident); !ok {
+		return false
+	}
+
 	_, ok = letStmt.Value.(*ast.MacroLiteral)
 	return ok
 }
@@ -42,12 +48,21 @@ func addMacro(stmt ast.Statement, env object.Environment) {
 		Env:        env,
 		Body:       macroLit.Body,
 	}
-	env.Set(letStmt.Name.Value, macro)
+	env.Set(letStmt.Name.(*ast.Ident).Value, macro)
 }
 
-// ExpandMacros expands defined macros and replaces AST nodes with the result of macro expansion.
-func ExpandMacros(program ast.Node, env object.Environment) ast.Node {
+// ExpandMacros expands defined macros and returns a copy of `program` in which AST nodes are
+// replaced with the result of macro expansion. `program` itself is left untouched.
+// It returns an error if a macro does not return a Quote object or its result does not fit into the
+// place of the macro call.
+func ExpandMacros(program ast.Node, env object.Environment) (ast.Node, error) {
+	var expandErr error
+
 	modifier := func(node ast.Node) ast.Node {
+		if expandErr != nil {
+			return node
+		}
+
 		call, ok := node.(*ast.CallExpression)
 		if !ok {
 			return node
@@ -61,15 +76,26 @@ func ExpandMacros(program ast.Node, env object.Environment) ast.Node {
 		args := quoteArgs(call)
 		evalEnv := extendMacroEnv(macro, args)
 
-		quote, ok := Eval(macro.Body, evalEnv).(*object.Quote)
+		evaluated := unwrapReturnValue(Eval(macro.Body, evalEnv))
+		if isError(evaluated) {
+			expandErr = fmt.Errorf("macro %s failed: %s", call.Function, evaluated.Inspect())
+			return node
+		}
+
+		quote, ok := evaluated.(*object.Quote)
 		if !ok {
-			panic("we only support returning AST-nodes from macros")
+			expandErr = fmt.Errorf("macro %s must return a Quote, got %s", call.Function, evaluated.Type())
+			return node
 		}
 
 		return quote.Node
 	}
 
-	return ast.Modify(program, modifier)
+	expanded, err := ast.Modify(ast.Clone(program), modifier)
+	if expandErr != nil {
+		return nil, expandErr
+	}
+	return expanded, err
 }
 
 func isMacroCall(call *ast.CallExpression, env object.Environment) (macro *object.Macro, ok bool) {
@@ -90,7 +116,7 @@ func isMacroCall(call *ast.CallExpression, env object.Environment) (macro *objec
 func quoteArgs(call *ast.CallExpression) []*object.Quote {
 	args := make([]*object.Quote, 0, len(call.Arguments))
 	for _, arg := range call.Arguments {
-		args = append(args, &object.Quote{Node: arg})
+		args = append(args, &object.Quote{Node: ast.Clone(arg)})
 	}
 	return args
 }
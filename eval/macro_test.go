@@ -105,13 +105,33 @@ func TestExpandMacros(t *testing.T) {
 			}
 			`,
 		},
+		{
+			input: `
+			let sign = macro(x) {
+				quote(match (unquote(x)) { 0 => 0, n if n < 0 => -1, _ => 1 });
+			};
+			sign(a - b);
+			`,
+			want: `match (a - b) { 0 => 0, n if n < 0 => -1, _ => 1 }`,
+		},
+		{
+			input: `
+			let twice = macro(call) { quote([unquote(call), unquote(call)]); };
+			twice(xs |> map(f));
+			`,
+			want: `[map(xs, f), map(xs, f)]`,
+		},
 	}
 
 	for _, tt := range tests {
 		program := testParseProgram(tt.input)
 		env := object.NewEnvironment()
 		DefineMacros(program, env)
-		got := ExpandMacros(program, env).String()
+		expanded, err := ExpandMacros(program, env)
+		if err != nil {
+			t.Fatalf("ExpandMacros returned an error: %s", err)
+		}
+		got := expanded.String()
 
 		want := testParseProgram(tt.want).String()
 		if got != want {
@@ -120,6 +140,91 @@ func TestExpandMacros(t *testing.T) {
 	}
 }
 
+func TestExpandMacrosTwice(t *testing.T) {
+	input := `
+	let double = macro(x) { quote(unquote(x) * 2); };
+	let result = double(1 + 1);
+	double(result);
+	`
+
+	program := testParseProgram(input)
+	orig := program.String()
+	env := object.NewEnvironment()
+	DefineMacros(program, env)
+	defined := program.String()
+
+	first, err := ExpandMacros(program, env)
+	if err != nil {
+		t.Fatalf("ExpandMacros returned an error: %s", err)
+	}
+	second, err := ExpandMacros(program, env)
+	if err != nil {
+		t.Fatalf("ExpandMacros returned an error: %s", err)
+	}
+
+	want := "let result = ((1 + 1) * 2);(result * 2)"
+	if got := first.String(); got != want {
+		t.Errorf("expected %q, but got %q", want, got)
+	}
+	if first.String() != second.String() {
+		t.Errorf("expanding twice gave different results: %q and %q", first, second)
+	}
+	if program.String() != defined {
+		t.Errorf("ExpandMacros modified the program: %q", program)
+	}
+
+	macro, _ := env.Get("double")
+	wantBody := "quote((unquote(x) * 2))"
+	if got := macro.(*object.Macro).Body.String(); got != wantBody {
+		t.Errorf("expansion modified the macro body (original program %q): got %q", orig, got)
+	}
+}
+
+func TestExpandMacrosErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{
+			input: `
+			let notQuote = macro() { 1 + 2; };
+			notQuote();
+			`,
+			want: "macro notQuote must return a Quote, got Integer",
+		},
+		{
+			input: `
+			let broken = macro() { quote(1) + 2; };
+			broken();
+			`,
+			want: "macro broken failed: Error: type mismatch: Quote + Integer",
+		},
+		{
+			input: `
+			let intoStatement = macro() { quote(unquote([1])); };
+			puts(intoStatement());
+			`,
+			want: "macro intoStatement failed: Error: could not quote unquote([1]): cannot unquote Array",
+		},
+	}
+
+	for _, tt := range tests {
+		program := testParseProgram(tt.input)
+		env := object.NewEnvironment()
+		DefineMacros(program, env)
+
+		_, err := ExpandMacros(program, env)
+		if err == nil {
+			t.Errorf("expected an error for %q", tt.input)
+			continue
+		}
+
+		if err.Error() != tt.want {
+			t.Errorf("expected error %q, but got %q", tt.want, err)
+		}
+	}
+}
+
 func testParseProgram(input string) *ast.Program {
 	return parser.New(lexer.New(input)).ParseProgram()
 }
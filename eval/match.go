@@ -0,0 +1,222 @@
+package eval
+
+import (
+	"fmt"
+
+	"github.com/skatsuta/monkey-interpreter/ast"
+	"github.com/skatsuta/monkey-interpreter/object"
+)
+
+// wildcard is the identifier which matches any value in a pattern without binding it.
+const wildcard = "_"
+
+// evalMatchExpression evaluates the body of the first arm whose pattern matches the value and whose
+// guard, if any, is truthy. Each arm is tried in its own environment holding the bindings of its
+// pattern, so that a failed arm leaves nothing behind.
+func evalMatchExpression(node *ast.MatchExpression, env object.Environment) object.Object {
+	value := Eval(node.Value, env)
+	if isError(value) {
+		return value
+	}
+
+	for _, arm := range node.Arms {
+		bindings := make(map[string]object.Object)
+		mismatch, err := matchPattern(arm.Pattern, value, env, bindings)
+		if err != nil {
+			return err
+		}
+		if mismatch != "" {
+			continue
+		}
+
+		armEnv := object.NewEnclosedEnvironment(env)
+		if err := declare(arm.Pattern, bindings, armEnv, false); err != nil {
+			return err
+		}
+
+		if arm.Guard != nil {
+			guard := Eval(arm.Guard, armEnv)
+			if isError(guard) {
+				return guard
+			}
+			if !isTruthy(guard) {
+				continue
+			}
+		}
+
+		return Eval(arm.Body, armEnv)
+	}
+
+	return newError("no match arm matches %s", value.Inspect())
+}
+
+// bind binds `target` of a let or const statement, a function parameter or a catch parameter, which
+// is an identifier or a pattern, to `value` in `env`, as constants if `constant` is true. It returns
+// an error and binds nothing if `value` does not have the shape of the pattern, or if declare
+// rejects a name.
+func bind(target ast.Expression, value object.Object, env object.Environment, constant bool) *object.Error {
+	bindings := make(map[string]object.Object)
+	if ident, ok := target.(*ast.Ident); ok {
+		bindings[ident.Value] = value
+	} else {
+		mismatch, err := matchPattern(target, value, env, bindings)
+		if err != nil {
+			return err
+		}
+		if mismatch != "" {
+			return newError("cannot destructure into %s: %s", target, mismatch)
+		}
+	}
+
+	return declare(target, bindings, env, constant)
+}
+
+// declare adds `bindings` of the names in `target` to `env`, as constants if `constant` is true. It
+// returns an error and adds nothing if a name would redeclare a constant of `env` or shadow a
+// builtin while builtins are strict.
+func declare(target ast.Expression, bindings map[string]object.Object, env object.Environment, constant bool) *object.Error {
+	// Check the names in the order of the pattern so that the error does not depend on the order of
+	// the map.
+	for _, name := range boundNames(target) {
+		if _, ok := bindings[name]; !ok {
+			continue
+		}
+		if env.IsConst(name) {
+			return newError("cannot redeclare constant %s", name)
+		}
+		if env.StrictBuiltins() && isBuiltin(name) {
+			return newError("cannot shadow builtin %s", name)
+		}
+	}
+
+	for name, v := range bindings {
+		if constant {
+			env.SetConst(name, v)
+		} else {
+			env.Set(name, v)
+		}
+	}
+	return nil
+}
+
+// boundNames returns the names which `target`, an identifier or a pattern, binds.
+func boundNames(target ast.Expression) []string {
+	switch target := target.(type) {
+	case *ast.Ident:
+		return []string{target.Value}
+	case *ast.ArrayPattern:
+		var names []string
+		for _, elem := range target.Elements {
+			names = append(names, boundNames(elem)...)
+		}
+		if target.Rest != nil {
+			names = append(names, boundNames(target.Rest)...)
+		}
+		return names
+	case *ast.HashPattern:
+		var names []string
+		for _, pair := range target.Pairs {
+			names = append(names, boundNames(pair.Value)...)
+		}
+		return names
+	default:
+		return nil
+	}
+}
+
+// matchPattern matches `value` against `pattern` and adds the values of the identifiers in
+// `pattern` to `bindings`. It returns a description of the first part of `value` which does not
+// match, or an empty string if `value` matches. Literals in `pattern` are evaluated in `env`.
+func matchPattern(pattern ast.Expression, value object.Object, env object.Environment, bindings map[string]object.Object) (string, *object.Error) {
+	switch pattern := pattern.(type) {
+	case *ast.Ident:
+		if pattern.Value != wildcard {
+			bindings[pattern.Value] = value
+		}
+		return "", nil
+
+	case *ast.ArrayPattern:
+		return matchArrayPattern(pattern, value, env, bindings)
+
+	case *ast.HashPattern:
+		return matchHashPattern(pattern, value, env, bindings)
+
+	case *ast.IntegerLiteral, *ast.FloatLiteral, *ast.StringLiteral, *ast.Boolean, *ast.PrefixExpression:
+		literal := Eval(pattern, env)
+		if err, ok := literal.(*object.Error); ok {
+			return "", err
+		}
+		if !object.Equal(literal, value) {
+			return fmt.Sprintf("want %s, got %s", literal.Inspect(), value.Inspect()), nil
+		}
+		return "", nil
+
+	default:
+		return "", newError("invalid pattern: %s", pattern)
+	}
+}
+
+func matchArrayPattern(pattern *ast.ArrayPattern, value object.Object, env object.Environment, bindings map[string]object.Object) (string, *object.Error) {
+	arr, ok := value.(*object.Array)
+	if !ok {
+		return fmt.Sprintf("want Array, got %s", value.Type()), nil
+	}
+
+	n, l := len(pattern.Elements), len(arr.Elements)
+	switch {
+	case pattern.Rest == nil && l != n:
+		return fmt.Sprintf("want %d elements, got %d", n, l), nil
+	case l < n:
+		return fmt.Sprintf("want at least %d elements, got %d", n, l), nil
+	}
+
+	for i, elem := range pattern.Elements {
+		if mismatch, err := matchPattern(elem, arr.Elements[i], env, bindings); mismatch != "" || err != nil {
+			return mismatch, err
+		}
+	}
+
+	if pattern.Rest != nil && pattern.Rest.Value != wildcard {
+		rest := make([]object.Object, l-n)
+		copy(rest, arr.Elements[n:])
+		bindings[pattern.Rest.Value] = &object.Array{Elements: rest}
+	}
+
+	return "", nil
+}
+
+// matchHashPattern matches a hash having all the keys in the pattern, with values matching the
+// patterns of the keys. Other keys in the hash are ignored. A module matches as the hash of its
+// exports, so that `let {name} = import("path")` picks members out of it.
+func matchHashPattern(pattern *ast.HashPattern, value object.Object, env object.Environment, bindings map[string]object.Object) (string, *object.Error) {
+	var hash *object.Hash
+	switch value := value.(type) {
+	case *object.Hash:
+		hash = value
+	case *object.Module:
+		hash = value.Exports
+	default:
+		return fmt.Sprintf("want Hash, got %s", value.Type()), nil
+	}
+
+	for _, pair := range pattern.Pairs {
+		key := Eval(pair.Key, env)
+		if err, ok := key.(*object.Error); ok {
+			return "", err
+		}
+		hashable, ok := key.(object.Hashable)
+		if !ok {
+			return "", newError("unusable as hash key: %s", key.Type())
+		}
+
+		v, ok := hash.Get(hashable)
+		if !ok {
+			return fmt.Sprintf("missing key %s", key.Inspect()), nil
+		}
+		if mismatch, err := matchPattern(pair.Value, v, env, bindings); mismatch != "" || err != nil {
+			return mismatch, err
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,179 @@
+package eval
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/skatsuta/monkey-interpreter/ast"
+	"github.com/skatsuta/monkey-interpreter/lexer"
+	"github.com/skatsuta/monkey-interpreter/object"
+	"github.com/skatsuta/monkey-interpreter/parser"
+)
+
+func init() {
+	envBuiltins["import"] = builtinImport
+}
+
+// ModuleExt is the file extension of Monkey modules, which can be omitted in import paths.
+const ModuleExt = ".monkey"
+
+// Loader loads the source code of modules.
+type Loader interface {
+	// Load returns the source code of the module at `path`, which is a slash-separated path in the
+	// form accepted by fs.ValidPath.
+	Load(path string) ([]byte, error)
+}
+
+type fsLoader struct {
+	fsys fs.FS
+}
+
+// NewFSLoader returns a Loader which reads modules from files in `fsys`, e.g. os.DirFS for a
+// directory or fstest.MapFS for modules in memory.
+func NewFSLoader(fsys fs.FS) Loader {
+	return fsLoader{fsys: fsys}
+}
+
+func (l fsLoader) Load(path string) ([]byte, error) {
+	return fs.ReadFile(l.fsys, path)
+}
+
+// moduleCache holds the modules of a program shared by the importers of all its modules.
+type moduleCache struct {
+	loader  Loader
+	modules map[string]*object.Module
+	// loading is the chain of modules currently being loaded, which are importing one another.
+	loading []string
+}
+
+// importer implements object.Importer. Each module has its own importer, which resolves import paths
+// relative to the directory of the module.
+type importer struct {
+	cache *moduleCache
+	dir   string
+}
+
+// NewImporter returns an object.Importer which loads modules with `loader`. Paths imported by the
+// main program are relative to the root of the loader, and paths imported by a module are relative
+// to the directory of the module. Paths starting with "/" are always relative to the root, and
+// paths may not point outside of it.
+//
+// Each module is evaluated only once, in its own environment, and its top-level bindings are
+// exported. Modules are strict about shadowing builtins if the importing environment is. Importing a
+// module which is still being loaded is an error.
+func NewImporter(loader Loader) object.Importer {
+	return &importer{
+		cache: &moduleCache{
+			loader:  loader,
+			modules: make(map[string]*object.Module),
+		},
+		dir: ".",
+	}
+}
+
+func (imp *importer) Import(importPath string, from object.Environment) (*object.Module, error) {
+	modPath, err := imp.resolve(importPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cache := imp.cache
+	if mod, ok := cache.modules[modPath]; ok {
+		return mod, nil
+	}
+	for i, loading := range cache.loading {
+		if loading == modPath {
+			cycle := append(cache.loading[i:len(cache.loading):len(cache.loading)], modPath)
+			return nil, fmt.Errorf("import cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	src, err := cache.loader.Load(modPath)
+	if err != nil {
+		return nil, err
+	}
+
+	p := parser.New(lexer.New(string(src)))
+	program := p.ParseProgram()
+	if len(p.Errors()) > 0 {
+		return nil, fmt.Errorf("%s: %s", modPath, p.Errors()[0])
+	}
+
+	env := object.NewEnvironment(
+		object.WithOutput(from.Output()),
+		object.WithImporter(&importer{cache: cache, dir: path.Dir(modPath)}),
+		object.WithStrictBuiltins(from.StrictBuiltins()),
+	)
+
+	cache.loading = append(cache.loading, modPath)
+	result := Eval(program, env)
+	cache.loading = cache.loading[:len(cache.loading)-1]
+
+	if errObj, ok := result.(*object.Error); ok {
+		return nil, fmt.Errorf("%s: %s", modPath, errObj.Message)
+	}
+
+	mod := &object.Module{Path: modPath, Exports: object.NewHash(0)}
+	for _, stmt := range program.Statements {
+		if let, ok := stmt.(*ast.LetStatement); ok {
+			for _, name := range boundNames(let.Name) {
+				// A binding is missing if the module returned before defining it.
+				if value, ok := env.Get(name); ok {
+					mod.Exports.Set(&object.String{Value: name}, value)
+				}
+			}
+		}
+	}
+	cache.modules[modPath] = mod
+	return mod, nil
+}
+
+// resolve converts an import path into a path for the loader.
+func (imp *importer) resolve(importPath string) (string, error) {
+	if importPath == "" {
+		return "", errors.New("empty import path")
+	}
+
+	var modPath string
+	if strings.HasPrefix(importPath, "/") {
+		modPath = path.Clean(importPath[1:])
+	} else {
+		modPath = path.Join(imp.dir, importPath)
+	}
+	if path.Ext(modPath) == "" {
+		modPath += ModuleExt
+	}
+
+	if !fs.ValidPath(modPath) {
+		return "", errors.New("import path is outside of the module root")
+	}
+	return modPath, nil
+}
+
+// builtinImport implements `import(path)`, which returns the module at `path` imported by the
+// importer of `env`.
+func builtinImport(env object.Environment) object.BuiltinFunction {
+	return func(args ...object.Object) object.Object {
+		if err := checkArgs(args, 1, 1); err != nil {
+			return err
+		}
+		importPath, err := stringArg("import", args, 0)
+		if err != nil {
+			return err
+		}
+
+		imp := env.Importer()
+		if imp == nil {
+			return newError("cannot import %q: modules are not available", importPath)
+		}
+
+		mod, importErr := imp.Import(importPath, env)
+		if importErr != nil {
+			return newError("cannot import %q: %s", importPath, importErr)
+		}
+		return mod
+	}
+}
@@ -0,0 +1,166 @@
+package eval
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	"github.com/skatsuta/monkey-interpreter/lexer"
+	"github.com/skatsuta/monkey-interpreter/object"
+	"github.com/skatsuta/monkey-interpreter/parser"
+)
+
+func testEvalWithModules(t *testing.T, input string, fsys fstest.MapFS) (object.Object, string) {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) > 0 {
+		t.Fatalf("input %q has errors: %v", input, p.Errors())
+	}
+
+	var out bytes.Buffer
+	env := object.NewEnvironment(
+		object.WithOutput(&out),
+		object.WithImporter(NewImporter(NewFSLoader(fsys))),
+	)
+	return Eval(program, env), out.String()
+}
+
+func file(src string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(src)}
+}
+
+func TestImport(t *testing.T) {
+	fsys := fstest.MapFS{
+		"math.monkey": file(`
+			let square = fn(x) { x * x };
+			let answer = 42;
+			let helper = import("lib/helper");
+			let twice = fn(x) { helper["double"](x) };
+		`),
+		"lib/helper.monkey":  file(`let double = fn(x) { x * 2 }; let sibling = import("sibling")["name"];`),
+		"lib/sibling.monkey": file(`let name = "sibling"; let n = import("/counter.monkey")["n"];`),
+		"lib/up.monkey":      file(`let m = import("../math");`),
+		"counter.monkey":     file(`let n = 1;`),
+		"early.monkey":       file(`let a = 1; return 0; let b = 2;`),
+		"point.monkey":       file(`let [x, y, .._] = [1, 2, 3]; let {z: depth} = {"z": 3};`),
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let m = import("math"); m["square"](5)`, "25"},
+		{`import("math.monkey")["answer"]`, "42"},
+		{`import("math")["twice"](4)`, "8"},
+		{`import("lib/helper")["sibling"]`, "sibling"},
+		{`import("lib/up")["m"]["answer"]`, "42"},
+		{`import("math")`, "module(math.monkey)"},
+		{`type(import("math"))`, "Module"},
+		{`import("early")["a"]`, "1"},
+		{`import("lib/sibling")["n"]`, "1"},
+		{`import("early")["b"]`, "Error: module early.monkey has no member b"},
+		{`import("math")["nothing"]`, "Error: module math.monkey has no member nothing"},
+		{`import("math")[1]`, "Error: index operator not supported: Module"},
+		{`import("math").square(3)`, "9"},
+		{`import("math").helper.double(4)`, "8"},
+		{`import("math").answer`, "42"},
+		{`let {square, answer: a} = import("math"); square(a)`, "1764"},
+		{`let p = import("point"); [p.x, p.y, p.depth]`, "[1, 2, 3]"},
+		{`import("point")["_"]`, "Error: module point.monkey has no member _"},
+		{`import("math").nothing()`, "Error: module math.monkey has no member nothing"},
+		{`import("missing")`, `Error: cannot import "missing": open missing.monkey: file does not exist`},
+		{`import("../outside")`, `Error: cannot import "../outside": import path is outside of the module root`},
+		{`import("")`, `Error: cannot import "": empty import path`},
+		{`import(1)`, "Error: first argument to `import` must be String, got Integer"},
+	}
+
+	for _, tt := range tests {
+		evaluated, _ := testEvalWithModules(t, tt.input, fsys)
+		if got := evaluated.Inspect(); got != tt.expected {
+			t.Errorf("%s: expected %q, but got %q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestImportEvaluatesModuleOnce(t *testing.T) {
+	fsys := fstest.MapFS{
+		"counter.monkey": file(`puts("loading counter"); let n = 1;`),
+		"user.monkey":    file(`let c = import("counter");`),
+	}
+	input := `
+	let a = import("counter");
+	let b = import("./counter.monkey");
+	import("user")["c"] == a
+	`
+
+	evaluated, out := testEvalWithModules(t, input, fsys)
+	testBooleanObject(t, evaluated, true)
+	if want := "loading counter\n"; out != want {
+		t.Errorf("expected output %q, but got %q", want, out)
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.monkey":      file(`let b = import("b");`),
+		"b.monkey":      file(`let c = import("c");`),
+		"c.monkey":      file(`let a = import("a");`),
+		"self.monkey":   file(`let me = import("self");`),
+		"broken.monkey": file(`let x = ;`),
+		"failing.monkey": file(`
+			let ok = 1;
+			let bad = 1 + true;
+		`),
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			`import("a")`,
+			`cannot import "a": a.monkey: cannot import "b": b.monkey: cannot import "c": ` +
+				`c.monkey: cannot import "a": import cycle: a.monkey -> b.monkey -> c.monkey -> a.monkey`,
+		},
+		{`import("self")`, `cannot import "self": self.monkey: cannot import "self": import cycle: self.monkey -> self.monkey`},
+		{`import("broken")`, `cannot import "broken": broken.monkey: no prefix parse function for ; found`},
+		{`import("failing")`, `cannot import "failing": failing.monkey: type mismatch: Integer + Boolean`},
+	}
+
+	for _, tt := range tests {
+		evaluated, _ := testEvalWithModules(t, tt.input, fsys)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("%s: no error object returned. got=%#v", tt.input, evaluated)
+			continue
+		}
+		if errObj.Message != tt.expected {
+			t.Errorf("%s: wrong error message.\nexpected=%q\ngot=     %q", tt.input, tt.expected, errObj.Message)
+		}
+	}
+}
+
+func TestImportWithStrictBuiltins(t *testing.T) {
+	fsys := fstest.MapFS{
+		"lib.monkey": file(`let len = fn(x) { 0 };`),
+	}
+	p := parser.New(lexer.New(`import("lib")`))
+	program := p.ParseProgram()
+
+	env := object.NewEnvironment(
+		object.WithImporter(NewImporter(NewFSLoader(fsys))),
+		object.WithStrictBuiltins(true),
+	)
+	want := `Error: cannot import "lib": lib.monkey: cannot shadow builtin len`
+	if got := Eval(program, env).Inspect(); got != want {
+		t.Errorf("expected %q, but got %q", want, got)
+	}
+}
+
+func TestImportWithoutImporter(t *testing.T) {
+	evaluated := testEval(t, `import("math")`)
+	want := `Error: cannot import "math": modules are not available`
+	if got := evaluated.Inspect(); got != want {
+		t.Errorf("expected %q, but got %q", want, got)
+	}
+}
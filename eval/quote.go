@@ -1,6 +1,8 @@
 package eval
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
 
 	"github.com/skatsuta/monkey-interpreter/ast"
@@ -16,21 +18,46 @@ const (
 )
 
 func quote(node ast.Node, env object.Environment) object.Object {
-	node = evalUnquoteCalls(node, env)
-	return &object.Quote{Node: node}
+	// Work on a copy so that evaluating the same quote again, e.g. in a function body or a macro,
+	// always starts from the original unquote calls.
+	quoted, err := evalUnquoteCalls(ast.Clone(node), env)
+	if err != nil {
+		return newError("could not quote %s: %s", node, err)
+	}
+	return &object.Quote{Node: quoted}
 }
 
-func evalUnquoteCalls(quoted ast.Node, env object.Environment) ast.Node {
+func evalUnquoteCalls(quoted ast.Node, env object.Environment) (ast.Node, error) {
+	var unquoteErr error
+
 	modifier := func(node ast.Node) ast.Node {
 		call, ok := node.(*ast.CallExpression)
 		if !ok || call.Function.TokenLiteral() != FuncNameUnquote || len(call.Arguments) != 1 {
 			return node
 		}
+		if unquoteErr != nil {
+			return node
+		}
 
 		unquoted := Eval(call.Arguments[0], env)
-		return convertObjectToASTNode(unquoted)
+		if errObj, ok := unquoted.(*object.Error); ok {
+			unquoteErr = errors.New(errObj.Message)
+			return node
+		}
+
+		converted := convertObjectToASTNode(unquoted)
+		if converted == nil {
+			unquoteErr = fmt.Errorf("cannot unquote %s", unquoted.Type())
+			return node
+		}
+		return converted
+	}
+
+	modified, err := ast.Modify(quoted, modifier)
+	if unquoteErr != nil {
+		return nil, unquoteErr
 	}
-	return ast.Modify(quoted, modifier)
+	return modified, err
 }
 
 func convertObjectToASTNode(obj object.Object) ast.Node {
@@ -51,7 +78,7 @@ func convertObjectToASTNode(obj object.Object) ast.Node {
 		}
 		return &ast.Boolean{Token: t, Value: obj.Value}
 	case *object.Quote:
-		return obj.Node
+		return ast.Clone(obj.Node)
 	default:
 		return nil
 	}
@@ -52,6 +52,14 @@ func TestQuoteUnquote(t *testing.T) {
 		   quote(unquote(4 + 4) + unquote(quotedInfixExpr))`,
 			`(8 + (4 + 4))`,
 		},
+		{
+			`quote(puts(unquote(1 + 2), unquote(true)))`,
+			`puts(3, true)`,
+		},
+		{
+			`let f = fn(x) { x * 2 }; quote(f(unquote(f(2))))`,
+			`f(4)`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -71,3 +79,57 @@ func TestQuoteUnquote(t *testing.T) {
 		}
 	}
 }
+
+func TestQuoteInFunctionBody(t *testing.T) {
+	input := `
+	let f = fn(x) { quote(unquote(x) + 1) };
+	let a = f(1);
+	let b = f(2);
+	[a, b, f(3)]
+	`
+
+	evaluated := testEval(t, input)
+	arr, ok := evaluated.(*object.Array)
+	if !ok {
+		t.Fatalf("expected *object.Array, but got %T (%#v)", evaluated, evaluated)
+	}
+
+	want := []string{"(1 + 1)", "(2 + 1)", "(3 + 1)"}
+	for i, elem := range arr.Elements {
+		if got := elem.(*object.Quote).Node.String(); got != want[i] {
+			t.Errorf("expected %q, but got %q", want[i], got)
+		}
+	}
+}
+
+func TestQuoteUnquoteErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{
+			`quote(unquote([1, 2]))`,
+			"could not quote unquote([1, 2]): cannot unquote Array",
+		},
+		{
+			`quote([unquote(fn(x) { x })])`,
+			"could not quote [unquote(fn(x) x)]: cannot unquote Function",
+		},
+		{
+			`quote(1 + unquote(foobar))`,
+			"could not quote (1 + unquote(foobar)): identifier not found: foobar",
+		},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Fatalf("expected *object.Error, but got %T (%#v)", evaluated, evaluated)
+		}
+
+		if errObj.Message != tt.want {
+			t.Errorf("expected %q, but got %q", tt.want, errObj.Message)
+		}
+	}
+}
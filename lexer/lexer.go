@@ -53,6 +53,13 @@ func (l *lexer) NextToken() token.Token {
 				Type:    token.EQ,
 				Literal: string(ch) + string(l.ch),
 			}
+		} else if l.peekChar() == '>' {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{
+				Type:    token.ARROW,
+				Literal: string(ch) + string(l.ch),
+			}
 		} else {
 			tok = newToken(token.ASSIGN, l.ch)
 		}
@@ -71,6 +78,19 @@ func (l *lexer) NextToken() token.Token {
 		tok = newToken(token.SEMICOLON, l.ch)
 	case ':':
 		tok = newToken(token.COLON, l.ch)
+	case '?':
+		tok = newToken(token.QUESTION, l.ch)
+	case '.':
+		if l.peekChar() == '.' {
+			l.readChar()
+			tok = token.Token{Type: token.DOTDOT, Literal: ".."}
+			if l.peekChar() == '.' {
+				l.readChar()
+				tok = token.Token{Type: token.ELLIPSIS, Literal: "..."}
+			}
+		} else {
+			tok = newToken(token.DOT, l.ch)
+		}
 	case '(':
 		tok = newToken(token.LPAREN, l.ch)
 	case ')':
@@ -86,9 +106,38 @@ func (l *lexer) NextToken() token.Token {
 	case '/':
 		tok = newToken(token.SLASH, l.ch)
 	case '<':
-		tok = newToken(token.LT, l.ch)
+		if l.peekChar() == '=' {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{
+				Type:    token.LTE,
+				Literal: string(ch) + string(l.ch),
+			}
+		} else {
+			tok = newToken(token.LT, l.ch)
+		}
 	case '>':
-		tok = newToken(token.GT, l.ch)
+		if l.peekChar() == '=' {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{
+				Type:    token.GTE,
+				Literal: string(ch) + string(l.ch),
+			}
+		} else {
+			tok = newToken(token.GT, l.ch)
+		}
+	case '|':
+		if l.peekChar() == '>' {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{
+				Type:    token.PIPE,
+				Literal: string(ch) + string(l.ch),
+			}
+		} else {
+			tok = newToken(token.ILLEGAL, l.ch)
+		}
 	case '{':
 		tok = newToken(token.LBRACE, l.ch)
 	case '}':
@@ -160,8 +209,9 @@ func (l *lexer) read(checkFn func(byte) bool) string {
 	return l.input[position:l.position]
 }
 
+// readIdent reads an identifier, which starts with a letter and may contain digits after it.
 func (l *lexer) readIdent() string {
-	return l.read(isLetter)
+	return l.read(func(ch byte) bool { return isLetter(ch) || isDigit(ch) })
 }
 
 func (l *lexer) readNumber() string {
@@ -170,7 +220,8 @@ func (l *lexer) readNumber() string {
 
 func (l *lexer) readNumberToken() token.Token {
 	intPart := l.readNumber()
-	if l.ch != '.' {
+	// A dot not followed by a digit is a member access, e.g. `5.abs()`.
+	if l.ch != '.' || !isDigit(l.peekChar()) {
 		return token.Token{
 			Type:    token.INT,
 			Literal: intPart,
@@ -2,10 +2,12 @@ package main
 
 import (
 	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 
 	"github.com/skatsuta/monkey-interpreter/eval"
 	"github.com/skatsuta/monkey-interpreter/lexer"
@@ -15,22 +17,25 @@ import (
 )
 
 func main() {
+	strict := flag.Bool("strict", false, "make it an error to shadow builtins")
+	flag.Parse()
+
 	// Start Monkey REPL
-	if len(os.Args) == 1 {
+	if flag.NArg() == 0 {
 		fmt.Println("This is the Monkey programming language!")
 		fmt.Println("Feel free to type in commands")
-		repl.Start(os.Stdin, os.Stdout)
+		repl.Start(os.Stdin, os.Stdout, object.WithStrictBuiltins(*strict))
 		return
 	}
 
 	// Run a Monkey script
-	if err := runProgram(os.Args[1]); err != nil {
+	if err := runProgram(flag.Arg(0), *strict); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 }
 
-func runProgram(filename string) error {
+func runProgram(filename string, strict bool) error {
 	data, err := ioutil.ReadFile(filename)
 	if err != nil {
 		return fmt.Errorf("could not read %s: %v", filename, err)
@@ -42,7 +47,12 @@ func runProgram(filename string) error {
 		return errors.New(p.Errors()[0])
 	}
 
-	env := object.NewEnvironment()
+	// Modules are imported relative to the directory of the script.
+	loader := eval.NewFSLoader(os.DirFS(filepath.Dir(filename)))
+	env := object.NewEnvironment(
+		object.WithImporter(eval.NewImporter(loader)),
+		object.WithStrictBuiltins(strict),
+	)
 	result := eval.Eval(program, env)
 	if _, ok := result.(*object.Nil); ok {
 		return nil
@@ -0,0 +1,138 @@
+package object
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Compare returns an integer comparing a and b. The result is 0 if a and b are equal,
+// -1 if a is less than b, and +1 if a is greater than b. The ordering is:
+//
+//   - Integers and floats are ordered by their numeric values and may be compared with each other.
+//   - Strings are ordered lexicographically byte-wise.
+//   - Booleans are ordered with false before true.
+//   - All nils are equal.
+//   - Arrays are ordered lexicographically by their elements; a shorter array that is a prefix of
+//     a longer one comes first.
+//
+// Compare returns an error if a and b, or a pair of their elements, cannot be compared with each
+// other, including NaN. Compare is consistent with Equal: it returns 0 exactly if Equal reports
+// true for the same arguments.
+func Compare(a, b Object) (int, error) {
+	switch a := a.(type) {
+	case *Integer:
+		switch b := b.(type) {
+		case *Integer:
+			return compareInts(a.Value, b.Value), nil
+		case *Float:
+			c, err := compareFloatWithInt(b.Value, a.Value)
+			return -c, err
+		}
+
+	case *Float:
+		switch b := b.(type) {
+		case *Integer:
+			return compareFloatWithInt(a.Value, b.Value)
+		case *Float:
+			if math.IsNaN(a.Value) || math.IsNaN(b.Value) {
+				return 0, errNaN
+			}
+			return compareFloats(a.Value, b.Value), nil
+		}
+
+	case *String:
+		if b, ok := b.(*String); ok {
+			return strings.Compare(a.Value, b.Value), nil
+		}
+
+	case *Boolean:
+		if b, ok := b.(*Boolean); ok {
+			return compareBools(a.Value, b.Value), nil
+		}
+
+	case *Nil:
+		if _, ok := b.(*Nil); ok {
+			return 0, nil
+		}
+
+	case *Array:
+		if b, ok := b.(*Array); ok {
+			return compareArrays(a, b)
+		}
+	}
+
+	return 0, fmt.Errorf("cannot compare %s with %s", typeOf(a), typeOf(b))
+}
+
+var errNaN = errors.New("cannot compare NaN")
+
+func typeOf(obj Object) Type {
+	if obj == nil {
+		return "nil"
+	}
+	return obj.Type()
+}
+
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// compareFloatWithInt compares f with i without losing the precision of large integers.
+func compareFloatWithInt(f float64, i int64) (int, error) {
+	if math.IsNaN(f) {
+		return 0, errNaN
+	}
+	switch {
+	case f >= math.MaxInt64:
+		return 1, nil
+	case f < math.MinInt64:
+		return -1, nil
+	}
+	if fi, ok := floatToInt(f); ok {
+		return compareInts(fi, i), nil
+	}
+	// f is not integral, so its magnitude is less than 2^52 and converting i to float64 keeps their
+	// order even if i itself is rounded.
+	return compareFloats(f, float64(i)), nil
+}
+
+func compareBools(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case b:
+		return -1
+	default:
+		return 1
+	}
+}
+
+func compareArrays(a, b *Array) (int, error) {
+	for i := 0; i < len(a.Elements) && i < len(b.Elements); i++ {
+		c, err := Compare(a.Elements[i], b.Elements[i])
+		if err != nil || c != 0 {
+			return c, err
+		}
+	}
+	return compareInts(int64(len(a.Elements)), int64(len(b.Elements))), nil
+}
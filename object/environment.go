@@ -1,5 +1,10 @@
 package object
 
+import (
+	"io"
+	"os"
+)
+
 // Environment associates values with variable names.
 type Environment interface {
 	// Get retrieves the value of a variable named by the `name`.
@@ -9,23 +14,82 @@ type Environment interface {
 
 	// Set sets the `val` of a variable named by the `name` and returns the `val` itself.
 	Set(name string, val Object) Object
+
+	// SetConst sets the `val` of a constant named by the `name` and returns the `val` itself.
+	SetConst(name string, val Object) Object
+
+	// IsConst reports whether the `name` is a constant defined in the environment itself, not in
+	// one of its outer environments.
+	IsConst(name string) bool
+
+	// Output returns the writer to which output builtins such as `puts` write.
+	Output() io.Writer
+
+	// Importer returns the importer which loads modules for `import`, or nil if the environment
+	// cannot import modules.
+	Importer() Importer
+
+	// StrictBuiltins reports whether bindings may not shadow builtins.
+	StrictBuiltins() bool
+}
+
+// Importer imports modules.
+type Importer interface {
+	// Import returns the module at `path`, which is relative to the module the importer belongs to.
+	// `from` is the environment in which `import` is called.
+	Import(path string, from Environment) (*Module, error)
 }
 
 // environment implements Environment interface.
 // environment is not thread safe, so do not use it in multiple goroutines.
 type environment struct {
-	store map[string]Object
-	outer Environment
+	store    map[string]Object
+	consts   map[string]bool
+	outer    Environment
+	output   io.Writer
+	importer Importer
+	strict   bool
 }
 
-// NewEnvironment returns a new Environment.
-func NewEnvironment() Environment {
-	return &environment{
-		store: make(map[string]Object),
-		outer: nil,
+// EnvironmentOption configures a new Environment.
+type EnvironmentOption func(*environment)
+
+// WithOutput makes the output of an Environment go to `w` instead of the standard output.
+func WithOutput(w io.Writer) EnvironmentOption {
+	return func(e *environment) {
+		e.output = w
 	}
 }
 
+// WithImporter makes an Environment import modules with `importer`.
+func WithImporter(importer Importer) EnvironmentOption {
+	return func(e *environment) {
+		e.importer = importer
+	}
+}
+
+// WithStrictBuiltins makes it an error for bindings in an Environment to shadow builtins if
+// `strict` is true.
+func WithStrictBuiltins(strict bool) EnvironmentOption {
+	return func(e *environment) {
+		e.strict = strict
+	}
+}
+
+// NewEnvironment returns a new Environment configured by `options`.
+// By default, its output goes to the standard output, and it cannot import modules.
+func NewEnvironment(options ...EnvironmentOption) Environment {
+	e := &environment{
+		store:  make(map[string]Object),
+		outer:  nil,
+		output: os.Stdout,
+	}
+	for _, option := range options {
+		option(e)
+	}
+	return e
+}
+
 // Get retrieves the value of a variable named by the `name`.
 // If the variable is present in the environment the value is returned and the boolean is true.
 // Otherwise the returned value will be nil and the boolean will be false.
@@ -40,9 +104,53 @@ func (e *environment) Get(name string) (Object, bool) {
 // Set sets the `val` of a variable named by the `name` and returns the `val` itself.
 func (e *environment) Set(name string, val Object) Object {
 	e.store[name] = val
+	delete(e.consts, name)
 	return val
 }
 
+// SetConst sets the `val` of a constant named by the `name` and returns the `val` itself.
+func (e *environment) SetConst(name string, val Object) Object {
+	if e.consts == nil {
+		e.consts = make(map[string]bool)
+	}
+	e.store[name] = val
+	e.consts[name] = true
+	return val
+}
+
+// IsConst reports whether the `name` is a constant defined in the environment itself, not in one
+// of its outer environments.
+func (e *environment) IsConst(name string) bool {
+	return e.consts[name]
+}
+
+// Output returns the writer to which output builtins such as `puts` write.
+// An enclosed environment shares the output of its outer environment.
+func (e *environment) Output() io.Writer {
+	if e.outer != nil {
+		return e.outer.Output()
+	}
+	return e.output
+}
+
+// Importer returns the importer which loads modules for `import`, or nil if the environment
+// cannot import modules. An enclosed environment shares the importer of its outer environment.
+func (e *environment) Importer() Importer {
+	if e.outer != nil {
+		return e.outer.Importer()
+	}
+	return e.importer
+}
+
+// StrictBuiltins reports whether bindings may not shadow builtins.
+// An enclosed environment shares the setting of its outer environment.
+func (e *environment) StrictBuiltins() bool {
+	if e.outer != nil {
+		return e.outer.StrictBuiltins()
+	}
+	return e.strict
+}
+
 // NewEnclosedEnvironment creates a new Environment which holds the given outer Environment.
 func NewEnclosedEnvironment(outer Environment) Environment {
 	return &environment{
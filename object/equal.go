@@ -0,0 +1,97 @@
+package object
+
+import "math"
+
+// Equal reports whether a and b are equal Monkey values. The equality model is:
+//
+//   - Integers and floats are compared by their numeric values, so 1 and 1.0 are equal.
+//     An integer equals a float only if the float holds exactly that integer. NaN equals nothing.
+//   - Strings and booleans are compared by their values.
+//   - All nils are equal, and a nil interface is equal only to another nil interface.
+//   - Arrays are equal if they have the same length and their elements are pairwise equal.
+//   - Hashes are equal if they have the same set of keys and equal values for each key;
+//     the order of the keys does not matter.
+//   - Builtins bound to an environment are equal if they have the same name, whichever
+//     environment they are bound to.
+//   - Any other objects, such as functions and builtins, are equal only to themselves.
+//
+// Equal is consistent with HashKey: any two equal hashable objects have the same HashKey.
+func Equal(a, b Object) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	switch a := a.(type) {
+	case *Integer:
+		switch b := b.(type) {
+		case *Integer:
+			return a.Value == b.Value
+		case *Float:
+			i, ok := floatToInt(b.Value)
+			return ok && a.Value == i
+		}
+		return false
+
+	case *Float:
+		switch b := b.(type) {
+		case *Integer:
+			i, ok := floatToInt(a.Value)
+			return ok && i == b.Value
+		case *Float:
+			return a.Value == b.Value
+		}
+		return false
+
+	case *String:
+		b, ok := b.(*String)
+		return ok && a.Value == b.Value
+
+	case *Boolean:
+		b, ok := b.(*Boolean)
+		return ok && a.Value == b.Value
+
+	case *Nil:
+		_, ok := b.(*Nil)
+		return ok
+
+	case *Array:
+		b, ok := b.(*Array)
+		if !ok || len(a.Elements) != len(b.Elements) {
+			return false
+		}
+		for i := range a.Elements {
+			if !Equal(a.Elements[i], b.Elements[i]) {
+				return false
+			}
+		}
+		return true
+
+	case *Hash:
+		b, ok := b.(*Hash)
+		if !ok || a.Len() != b.Len() {
+			return false
+		}
+		for _, pair := range a.pairs {
+			value, ok := b.Get(pair.Key.(Hashable))
+			if !ok || !Equal(pair.Value, value) {
+				return false
+			}
+		}
+		return true
+
+	case *Builtin:
+		b, ok := b.(*Builtin)
+		return ok && (a == b || a.Name != "" && a.Name == b.Name)
+
+	default:
+		return a == b
+	}
+}
+
+// floatToInt converts f to an integer if f holds an integral value representable as int64.
+func floatToInt(f float64) (int64, bool) {
+	if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, false
+	}
+	return int64(f), true
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"hash/fnv"
+	"math"
 	"strconv"
 	"strings"
 
@@ -40,6 +41,8 @@ const (
 	QuoteType = "Quote"
 	// MacroType represents a type of macros.
 	MacroType = "Macro"
+	// ModuleType represents a type of modules.
+	ModuleType = "Module"
 )
 
 // Object represents an object of Monkey language.
@@ -49,13 +52,23 @@ type Object interface {
 }
 
 // HashKey represents a key of a hash.
+// Different objects may have the same HashKey, so it must not be used to identify an object alone.
 type HashKey struct {
 	Type  Type
 	Value uint64
 }
 
+// hashBytes computes hash values of strings and floats. It is a variable so that tests can replace
+// it to force hash collisions.
+var hashBytes = func(b []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(b)
+	return h.Sum64()
+}
+
 // Hashable is the interface that is able to become a hash key.
 type Hashable interface {
+	Object
 	HashKey() HashKey
 }
 
@@ -93,19 +106,32 @@ func (f *Float) Type() Type {
 }
 
 // Inspect returns a string representation of f.
+// Special values are represented as NaN, Inf and -Inf.
 func (f *Float) Inspect() string {
-	return strconv.FormatFloat(f.Value, 'f', -1, 64)
+	switch {
+	case math.IsNaN(f.Value):
+		return "NaN"
+	case math.IsInf(f.Value, 1):
+		return "Inf"
+	case math.IsInf(f.Value, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(f.Value, 'f', -1, 64)
+	}
 }
 
 // HashKey returns a hash key object for f.
+// A float holding an integral value has the same hash key as the equal Integer.
 func (f *Float) HashKey() HashKey {
+	if i, ok := floatToInt(f.Value); ok {
+		return (&Integer{Value: i}).HashKey()
+	}
+
 	s := strconv.FormatFloat(f.Value, 'f', -1, 64)
-	h := fnv.New64a()
-	h.Write([]byte(s))
 
 	return HashKey{
 		Type:  f.Type(),
-		Value: h.Sum64(),
+		Value: hashBytes([]byte(s)),
 	}
 }
 
@@ -161,9 +187,15 @@ func (rv *ReturnValue) Inspect() string {
 	return rv.Value.Inspect()
 }
 
-// Error represents an error.
+// Error represents an error, either raised by the interpreter or thrown by `throw`.
 type Error struct {
 	Message string
+	// Kind classifies the error. It is empty for errors raised by the interpreter itself.
+	Kind string
+	// Value is the value passed to `throw`, or nil if the error was raised by the interpreter.
+	Value Object
+	// Stack lists the calls the error has propagated through, innermost first.
+	Stack []string
 }
 
 // Type returns the type of the Error.
@@ -173,12 +205,16 @@ func (e *Error) Type() Type {
 
 // Inspect returns a string representation of the Error.
 func (e *Error) Inspect() string {
-	return "Error: " + e.Message
+	if e.Kind == "" {
+		return "Error: " + e.Message
+	}
+	return e.Kind + ": " + e.Message
 }
 
 // Function represents a function.
 type Function struct {
-	Parameters []*ast.Ident
+	Parameters []ast.Expression
+	Rest       *ast.Ident // nil if the function takes no rest parameter
 	Body       *ast.BlockStatement
 	Env        Environment
 }
@@ -192,10 +228,13 @@ func (f *Function) Type() Type {
 func (f *Function) Inspect() string {
 	var out bytes.Buffer
 
-	params := make([]string, 0, len(f.Parameters))
+	params := make([]string, 0, len(f.Parameters)+1)
 	for _, p := range f.Parameters {
 		params = append(params, p.String())
 	}
+	if f.Rest != nil {
+		params = append(params, "..."+f.Rest.String())
+	}
 
 	out.WriteString("fn(")
 	out.WriteString(strings.Join(params, ", "))
@@ -223,12 +262,9 @@ func (s *String) Inspect() string {
 
 // HashKey returns a hash key object for s.
 func (s *String) HashKey() HashKey {
-	h := fnv.New64a()
-	h.Write([]byte(s.Value))
-
 	return HashKey{
 		Type:  s.Type(),
-		Value: h.Sum64(),
+		Value: hashBytes([]byte(s.Value)),
 	}
 }
 
@@ -238,6 +274,9 @@ type BuiltinFunction func(args ...Object) Object
 // Builtin represents a builtin function.
 type Builtin struct {
 	Fn BuiltinFunction
+	// Name is the name of a builtin bound to the environment in which it is looked up, which is a
+	// new Builtin on each lookup. It is empty for other builtins.
+	Name string
 }
 
 // Type returns the type of the Builtin.
@@ -284,9 +323,21 @@ type HashPair struct {
 	Value Object
 }
 
-// Hash represents a hash.
+// Hash represents a hash. It remembers the order in which keys were first inserted.
+// Keys with the same HashKey share a bucket and are told apart by comparing the key objects,
+// so a hash collision never makes two different keys overwrite each other.
+// The zero value is an empty hash ready to use.
 type Hash struct {
-	Pairs map[HashKey]HashPair
+	pairs   []HashPair
+	buckets map[HashKey][]int // positions in pairs of the keys sharing a HashKey
+}
+
+// NewHash returns a new empty Hash with room for `size` pairs.
+func NewHash(size int) *Hash {
+	return &Hash{
+		pairs:   make([]HashPair, 0, size),
+		buckets: make(map[HashKey][]int, size),
+	}
 }
 
 // Type returns the type of the Hash.
@@ -294,14 +345,66 @@ func (*Hash) Type() Type {
 	return HashType
 }
 
+// Len returns the number of pairs in h.
+func (h *Hash) Len() int {
+	return len(h.pairs)
+}
+
+// Get returns the value associated with `key`.
+// If the key is present in h the value is returned and the boolean is true.
+// Otherwise the returned value will be nil and the boolean will be false.
+func (h *Hash) Get(key Hashable) (Object, bool) {
+	i, _ := h.lookup(key)
+	if i < 0 {
+		return nil, false
+	}
+	return h.pairs[i].Value, true
+}
+
+// Set associates `value` with `key`. A new key is appended to the end of h, while an existing key
+// keeps its position and only its value is replaced.
+func (h *Hash) Set(key Hashable, value Object) {
+	i, hashed := h.lookup(key)
+	if i >= 0 {
+		h.pairs[i].Value = value
+		return
+	}
+
+	if h.buckets == nil {
+		h.buckets = make(map[HashKey][]int)
+	}
+	h.buckets[hashed] = append(h.buckets[hashed], len(h.pairs))
+	h.pairs = append(h.pairs, HashPair{Key: key, Value: value})
+}
+
+// lookup returns the position of `key` in h.pairs, or -1 if h does not contain `key`,
+// along with the HashKey of `key`.
+func (h *Hash) lookup(key Hashable) (int, HashKey) {
+	hashed := key.HashKey()
+	for _, i := range h.buckets[hashed] {
+		if Equal(h.pairs[i].Key, key) {
+			return i, hashed
+		}
+	}
+	return -1, hashed
+}
+
+// Pairs returns the key-value pairs of h in insertion order.
+// The returned slice is a copy, so modifying it does not affect h.
+func (h *Hash) Pairs() []HashPair {
+	pairs := make([]HashPair, len(h.pairs))
+	copy(pairs, h.pairs)
+	return pairs
+}
+
 // Inspect returns a string representation of the Hash.
 func (h *Hash) Inspect() string {
 	if h == nil {
 		return ""
 	}
 
-	pairs := make([]string, 0, len(h.Pairs))
-	for _, pair := range h.Pairs {
+	pairs := make([]string, 0, len(h.pairs))
+	for _, pair := range h.pairs {
 		pairs = append(pairs, pair.Key.Inspect()+": "+pair.Value.Inspect())
 	}
 
@@ -356,3 +459,21 @@ func (m *Macro) Inspect() string {
 
 	return out.String()
 }
+
+// Module represents a module loaded by `import`.
+type Module struct {
+	// Path is the path of the module source relative to the root of the module loader.
+	Path string
+	// Exports holds the top-level bindings of the module in the order they are defined.
+	Exports *Hash
+}
+
+// Type returns the type of `m`.
+func (m *Module) Type() Type {
+	return ModuleType
+}
+
+// Inspect returns a string representation of `m`.
+func (m *Module) Inspect() string {
+	return fmt.Sprintf("module(%s)", m.Path)
+}
@@ -1,6 +1,9 @@
 package object
 
-import "testing"
+import (
+	"math"
+	"testing"
+)
 
 func TestStringHashKey(t *testing.T) {
 	hello1 := &String{Value: "Hello World"}
@@ -67,3 +70,276 @@ func TestIntegerHashKey(t *testing.T) {
 			one1.HashKey(), two1.HashKey())
 	}
 }
+
+func TestFloatInspect(t *testing.T) {
+	tests := []struct {
+		value float64
+		want  string
+	}{
+		{1.5, "1.5"},
+		{-0.25, "-0.25"},
+		{2, "2"},
+		{1e21, "1000000000000000000000"},
+		{math.NaN(), "NaN"},
+		{math.Inf(1), "Inf"},
+		{math.Inf(-1), "-Inf"},
+	}
+
+	for _, tt := range tests {
+		if got := (&Float{Value: tt.value}).Inspect(); got != tt.want {
+			t.Errorf("expected %q, but got %q", tt.want, got)
+		}
+	}
+}
+
+func TestHashKeepsInsertionOrder(t *testing.T) {
+	h := NewHash(0)
+	h.Set(&String{Value: "b"}, &Integer{Value: 1})
+	h.Set(&Integer{Value: 10}, &Integer{Value: 2})
+	h.Set(&String{Value: "a"}, &Integer{Value: 3})
+	h.Set(&Boolean{Value: true}, &Integer{Value: 4})
+	h.Set(&String{Value: "b"}, &Integer{Value: 5})
+
+	want := "{b: 5, 10: 2, a: 3, true: 4}"
+	for i := 0; i < 10; i++ {
+		if got := h.Inspect(); got != want {
+			t.Fatalf("h.Inspect() wrong. want=%q, got=%q", want, got)
+		}
+	}
+
+	if l := h.Len(); l != 4 {
+		t.Errorf("h has wrong number of pairs. want=%d, got=%d", 4, l)
+	}
+
+	value, ok := h.Get(&String{Value: "b"})
+	if !ok {
+		t.Fatalf("no value for key %q", "b")
+	}
+	if got := value.(*Integer).Value; got != 5 {
+		t.Errorf("value for key %q is wrong. want=%d, got=%d", "b", 5, got)
+	}
+
+	if _, ok := h.Get(&String{Value: "c"}); ok {
+		t.Errorf("unexpected value for key %q", "c")
+	}
+
+	pairs := h.Pairs()
+	pairs[0].Value = &Integer{Value: 99}
+	if got := h.Inspect(); got != want {
+		t.Errorf("modifying Pairs() changed the hash: %q", got)
+	}
+}
+
+func TestZeroHash(t *testing.T) {
+	var h Hash
+	if _, ok := h.Get(&String{Value: "a"}); ok {
+		t.Errorf("zero Hash has a value")
+	}
+
+	h.Set(&String{Value: "a"}, &Integer{Value: 1})
+	if got, want := h.Inspect(), "{a: 1}"; got != want {
+		t.Errorf("h.Inspect() wrong. want=%q, got=%q", want, got)
+	}
+}
+
+func TestHashKeyCollisions(t *testing.T) {
+	orig := hashBytes
+	defer func() { hashBytes = orig }()
+	hashBytes = func([]byte) uint64 { return 42 }
+
+	a := &String{Value: "a"}
+	b := &String{Value: "b"}
+	if a.HashKey() != b.HashKey() {
+		t.Fatalf("expected colliding hash keys, got %#v and %#v", a.HashKey(), b.HashKey())
+	}
+
+	h := NewHash(0)
+	h.Set(a, &Integer{Value: 1})
+	h.Set(b, &Integer{Value: 2})
+	h.Set(&Float{Value: 1.5}, &Integer{Value: 3})
+	h.Set(&Float{Value: 2.5}, &Integer{Value: 4})
+	h.Set(&String{Value: "a"}, &Integer{Value: 5})
+
+	if l := h.Len(); l != 4 {
+		t.Fatalf("h has wrong number of pairs. want=%d, got=%d", 4, l)
+	}
+
+	tests := []struct {
+		key  Hashable
+		want int64
+	}{
+		{&String{Value: "a"}, 5},
+		{&String{Value: "b"}, 2},
+		{&Float{Value: 1.5}, 3},
+		{&Float{Value: 2.5}, 4},
+	}
+
+	for _, tt := range tests {
+		value, ok := h.Get(tt.key)
+		if !ok {
+			t.Errorf("no value for key %s", tt.key.Inspect())
+			continue
+		}
+		if got := value.(*Integer).Value; got != tt.want {
+			t.Errorf("value for key %s is wrong. want=%d, got=%d", tt.key.Inspect(), tt.want, got)
+		}
+	}
+
+	if _, ok := h.Get(&String{Value: "c"}); ok {
+		t.Errorf("unexpected value for colliding key %q", "c")
+	}
+
+	if got, want := h.Inspect(), "{a: 5, b: 2, 1.5: 3, 2.5: 4}"; got != want {
+		t.Errorf("h.Inspect() wrong. want=%q, got=%q", want, got)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	arr := func(elems ...Object) *Array { return &Array{Elements: elems} }
+	hash := func(kvs ...Object) *Hash {
+		h := NewHash(len(kvs) / 2)
+		for i := 0; i < len(kvs); i += 2 {
+			h.Set(kvs[i].(Hashable), kvs[i+1])
+		}
+		return h
+	}
+	i := func(v int64) *Integer { return &Integer{Value: v} }
+	f := func(v float64) *Float { return &Float{Value: v} }
+	s := func(v string) *String { return &String{Value: v} }
+	fn := &Function{}
+	builtin := &Builtin{}
+	nan := math.NaN()
+
+	tests := []struct {
+		a, b Object
+		want bool
+	}{
+		{nil, nil, true},
+		{nil, &Nil{}, false},
+		{&Nil{}, &Nil{}, true},
+		{i(1), i(1), true},
+		{i(1), i(2), false},
+		{i(1), f(1), true},
+		{f(1), i(1), true},
+		{f(1.5), i(1), false},
+		{i(1<<53 + 1), f(1 << 53), false},
+		{f(nan), f(nan), false},
+		{f(math.Inf(1)), f(math.Inf(1)), true},
+		{s("a"), s("a"), true},
+		{s("a"), s("b"), false},
+		{s("1"), i(1), false},
+		{&Boolean{Value: true}, &Boolean{Value: true}, true},
+		{&Boolean{Value: true}, i(1), false},
+		{arr(), arr(), true},
+		{arr(i(1), s("a")), arr(i(1), s("a")), true},
+		{arr(i(1), arr(i(2))), arr(f(1), arr(i(2))), true},
+		{arr(i(1), s("a")), arr(s("a"), i(1)), false},
+		{arr(i(1)), arr(i(1), i(1)), false},
+		{hash(), hash(), true},
+		{hash(s("a"), i(1), s("b"), arr(i(2))), hash(s("b"), arr(i(2)), s("a"), i(1)), true},
+		{hash(s("a"), i(1)), hash(s("a"), i(2)), false},
+		{hash(s("a"), i(1)), hash(s("b"), i(1)), false},
+		{hash(s("a"), i(1)), hash(s("a"), i(1), s("b"), i(1)), false},
+		{hash(i(1), s("x")), hash(f(1), s("x")), true},
+		{arr(), hash(), false},
+		{fn, fn, true},
+		{fn, &Function{}, false},
+		{builtin, builtin, true},
+		{builtin, &Builtin{}, false},
+		{&Builtin{Name: "puts"}, &Builtin{Name: "puts"}, true},
+		{&Builtin{Name: "puts"}, &Builtin{Name: "print"}, false},
+		{&Builtin{Name: "puts"}, &Builtin{}, false},
+	}
+
+	for _, tt := range tests {
+		if got := Equal(tt.a, tt.b); got != tt.want {
+			t.Errorf("Equal(%#v, %#v) wrong. want=%t, got=%t", tt.a, tt.b, tt.want, got)
+		}
+
+		ha, okA := tt.a.(Hashable)
+		hb, okB := tt.b.(Hashable)
+		if tt.want && okA && okB && ha.HashKey() != hb.HashKey() {
+			t.Errorf("equal objects %#v and %#v have different hash keys", tt.a, tt.b)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	arr := func(elems ...Object) *Array { return &Array{Elements: elems} }
+	i := func(v int64) *Integer { return &Integer{Value: v} }
+	f := func(v float64) *Float { return &Float{Value: v} }
+	s := func(v string) *String { return &String{Value: v} }
+
+	tests := []struct {
+		a, b Object
+		want int
+	}{
+		{i(1), i(2), -1},
+		{i(2), i(2), 0},
+		{i(3), i(2), 1},
+		{i(1), f(1.5), -1},
+		{f(1.5), i(1), 1},
+		{f(2), i(2), 0},
+		{i(1<<53 + 1), f(1 << 53), 1},
+		{i(math.MaxInt64), f(math.MaxInt64), -1},
+		{f(math.Inf(-1)), i(math.MinInt64), -1},
+		{f(0.5), f(0.25), 1},
+		{s("a"), s("b"), -1},
+		{s("b"), s("a"), 1},
+		{s("a"), s("a"), 0},
+		{s("Z"), s("a"), -1},
+		{s("a"), s("ab"), -1},
+		{&Boolean{Value: false}, &Boolean{Value: true}, -1},
+		{&Boolean{Value: true}, &Boolean{Value: true}, 0},
+		{&Nil{}, &Nil{}, 0},
+		{arr(), arr(), 0},
+		{arr(), arr(i(1)), -1},
+		{arr(i(1), s("b")), arr(i(1), s("a")), 1},
+		{arr(i(1), f(2)), arr(f(1), i(2)), 0},
+		{arr(arr(i(1)), i(9)), arr(arr(i(2))), -1},
+	}
+
+	for _, tt := range tests {
+		got, err := Compare(tt.a, tt.b)
+		if err != nil {
+			t.Errorf("Compare(%s, %s) returned an error: %s", tt.a.Inspect(), tt.b.Inspect(), err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Compare(%s, %s) wrong. want=%d, got=%d", tt.a.Inspect(), tt.b.Inspect(), tt.want, got)
+		}
+		if Equal(tt.a, tt.b) != (got == 0) {
+			t.Errorf("Compare(%s, %s) is inconsistent with Equal", tt.a.Inspect(), tt.b.Inspect())
+		}
+	}
+}
+
+func TestCompareErrors(t *testing.T) {
+	tests := []struct {
+		a, b Object
+		want string
+	}{
+		{&Integer{Value: 1}, &String{Value: "1"}, "cannot compare Integer with String"},
+		{&Boolean{}, &Integer{}, "cannot compare Boolean with Integer"},
+		{&Hash{}, &Hash{}, "cannot compare Hash with Hash"},
+		{&Function{}, &Function{}, "cannot compare Function with Function"},
+		{&Float{Value: math.NaN()}, &Float{Value: 1}, "cannot compare NaN"},
+		{&Integer{Value: 1}, &Float{Value: math.NaN()}, "cannot compare NaN"},
+		{
+			&Array{Elements: []Object{&Integer{Value: 1}, &Nil{}}},
+			&Array{Elements: []Object{&Integer{Value: 1}, &Integer{Value: 2}}},
+			"cannot compare Nil with Integer",
+		},
+	}
+
+	for _, tt := range tests {
+		_, err := Compare(tt.a, tt.b)
+		if err == nil {
+			t.Errorf("Compare(%s, %s) returned no error", tt.a.Inspect(), tt.b.Inspect())
+			continue
+		}
+		if err.Error() != tt.want {
+			t.Errorf("wrong error message. want=%q, got=%q", tt.want, err)
+		}
+	}
+}
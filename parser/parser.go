@@ -13,10 +13,14 @@ const (
 	_ int = iota
 	// LOWEST represents the lowest precedence.
 	LOWEST
+	// CONDITIONAL represents precedence of conditional expressions.
+	CONDITIONAL // X ? Y : Z
 	// EQUALS represents precedence of equals.
 	EQUALS // ==
 	// LESSGREATER represents precedence of less than or greater than.
-	LESSGREATER // > or <
+	LESSGREATER // >, <, >=, <= or in
+	// PIPELINE represents precedence of pipeline operator.
+	PIPELINE // X |> f
 	// SUM represents precedence of sum.
 	SUM // +
 	// PRODUCT represents precedence of product.
@@ -25,21 +29,27 @@ const (
 	PREFIX // -X or !X
 	// CALL represents precedence of function call.
 	CALL // myFunc(X)
-	// INDEX represents precedence of array index operator.
-	INDEX // array[index]
+	// INDEX represents precedence of array index and member access operators.
+	INDEX // array[index], array[low:high] or hash.key
 )
 
 var precedences = map[token.Type]int{
+	token.QUESTION: CONDITIONAL,
 	token.EQ:       EQUALS,
 	token.NEQ:      EQUALS,
 	token.LT:       LESSGREATER,
 	token.GT:       LESSGREATER,
+	token.LTE:      LESSGREATER,
+	token.GTE:      LESSGREATER,
+	token.IN:       LESSGREATER,
+	token.PIPE:     PIPELINE,
 	token.PLUS:     SUM,
 	token.MINUS:    SUM,
 	token.SLASH:    PRODUCT,
 	token.ASTARISK: PRODUCT,
 	token.LPAREN:   CALL,
 	token.LBRACKET: INDEX,
+	token.DOT:      INDEX,
 }
 
 type (
@@ -81,6 +91,8 @@ func New(l lexer.Lexer) *Parser {
 		token.LBRACKET: p.parseArrayLiteral,
 		token.LBRACE:   p.parseHashLiteral,
 		token.MACRO:    p.parseMacroLiteral,
+		token.TRY:      p.parseTryExpression,
+		token.MATCH:    p.parseMatchExpression,
 	}
 
 	p.infixParseFns = map[token.Type]infixParseFn{
@@ -92,8 +104,14 @@ func New(l lexer.Lexer) *Parser {
 		token.NEQ:      p.parseInfixExpression,
 		token.LT:       p.parseInfixExpression,
 		token.GT:       p.parseInfixExpression,
+		token.LTE:      p.parseInfixExpression,
+		token.GTE:      p.parseInfixExpression,
+		token.IN:       p.parseInfixExpression,
 		token.LPAREN:   p.parseCallExpression,
 		token.LBRACKET: p.parseIndexExpression,
+		token.DOT:      p.parseMemberExpression,
+		token.QUESTION: p.parseConditionalExpression,
+		token.PIPE:     p.parsePipeExpression,
 	}
 
 	// Read two tokens, so curToken and peekToken are both set
@@ -155,10 +173,12 @@ func (p *Parser) ParseProgram() *ast.Program {
 
 func (p *Parser) parseStatement() ast.Statement {
 	switch p.curToken.Type {
-	case token.LET:
+	case token.LET, token.CONST:
 		return p.parseLetStatement()
 	case token.RETURN:
 		return p.parseReturnStatement()
+	case token.THROW:
+		return p.parseThrowStatement()
 	default:
 		return p.parseExpressionStatement()
 	}
@@ -167,13 +187,10 @@ func (p *Parser) parseStatement() ast.Statement {
 func (p *Parser) parseLetStatement() *ast.LetStatement {
 	stmt := &ast.LetStatement{Token: p.curToken}
 
-	if !p.expectPeek(token.IDENT) {
-		return nil
-	}
+	p.nextToken()
 
-	stmt.Name = &ast.Ident{
-		Token: p.curToken,
-		Value: p.curToken.Literal,
+	if stmt.Name = p.parseBindingTarget(); stmt.Name == nil {
+		return nil
 	}
 
 	if !p.expectPeek(token.ASSIGN) {
@@ -207,6 +224,22 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	return stmt
 }
 
+func (p *Parser) parseThrowStatement() *ast.ThrowStatement {
+	stmt := &ast.ThrowStatement{
+		Token: p.curToken,
+	}
+
+	p.nextToken()
+
+	stmt.Value = p.parseExpression(LOWEST)
+
+	for p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
 func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 	stmt := &ast.ExpressionStatement{
 		Token:      p.curToken,
@@ -320,6 +353,30 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 	return expr
 }
 
+// parsePipeExpression parses `left |> right` into a call of `right` with `left` as its first
+// argument, so `x |> f(y)` is parsed as `f(x, y)` and `x |> f` as `f(x)`. Since no pipeline node
+// is left in the AST, macros and the evaluator only ever see the call.
+func (p *Parser) parsePipeExpression(left ast.Expression) ast.Expression {
+	tok := p.curToken
+	prec := p.curPrecedence()
+
+	p.nextToken()
+
+	right := p.parseExpression(prec)
+	if right == nil {
+		return nil
+	}
+
+	if call, ok := right.(*ast.CallExpression); ok {
+		args := make([]ast.Expression, 0, len(call.Arguments)+1)
+		args = append(args, left)
+		args = append(args, call.Arguments...)
+		return &ast.CallExpression{Token: call.Token, Function: call.Function, Arguments: args}
+	}
+
+	return &ast.CallExpression{Token: tok, Function: right, Arguments: []ast.Expression{left}}
+}
+
 func (p *Parser) parseBoolean() ast.Expression {
 	return &ast.Boolean{
 		Token: p.curToken,
@@ -362,6 +419,23 @@ func (p *Parser) parseIfExpression() ast.Expression {
 	if p.peekTokenIs(token.ELSE) {
 		p.nextToken()
 
+		// `else if` is parsed as an else block holding only the nested if expression.
+		if p.peekTokenIs(token.IF) {
+			p.nextToken()
+
+			tok := p.curToken
+			nested := p.parseIfExpression()
+			if nested == nil {
+				return nil
+			}
+
+			expr.Alternative = &ast.BlockStatement{
+				Token:      tok,
+				Statements: []ast.Statement{&ast.ExpressionStatement{Token: tok, Expression: nested}},
+			}
+			return expr
+		}
+
 		if !p.expectPeek(token.LBRACE) {
 			return nil
 		}
@@ -372,6 +446,74 @@ func (p *Parser) parseIfExpression() ast.Expression {
 	return expr
 }
 
+// parseConditionalExpression parses `condition ? consequence : alternative`. It is right
+// associative, so `a ? b : c ? d : e` is `a ? b : (c ? d : e)`.
+func (p *Parser) parseConditionalExpression(condition ast.Expression) ast.Expression {
+	expr := &ast.ConditionalExpression{Token: p.curToken, Condition: condition}
+
+	p.nextToken()
+	expr.Consequence = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.COLON) {
+		return nil
+	}
+
+	p.nextToken()
+	expr.Alternative = p.parseExpression(LOWEST)
+
+	return expr
+}
+
+func (p *Parser) parseTryExpression() ast.Expression {
+	expr := &ast.TryExpression{Token: p.curToken}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	expr.Block = p.parseBlockStatement()
+
+	if p.peekTokenIs(token.CATCH) {
+		p.nextToken()
+
+		if p.peekTokenIs(token.LPAREN) {
+			p.nextToken()
+
+			if !p.expectPeek(token.IDENT) {
+				return nil
+			}
+			expr.CatchParam = &ast.Ident{Token: p.curToken, Value: p.curToken.Literal}
+
+			if !p.expectPeek(token.RPAREN) {
+				return nil
+			}
+		}
+
+		if !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+
+		expr.Catch = p.parseBlockStatement()
+	}
+
+	if p.peekTokenIs(token.FINALLY) {
+		p.nextToken()
+
+		if !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+
+		expr.Finally = p.parseBlockStatement()
+	}
+
+	if expr.Catch == nil && expr.Finally == nil {
+		p.errors = append(p.errors, "expected catch or finally after try block")
+		return nil
+	}
+
+	return expr
+}
+
 func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 	block := &ast.BlockStatement{
 		Token:      p.curToken,
@@ -399,7 +541,7 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 		return nil
 	}
 
-	lit.Parameters = p.parseFunctionParameters()
+	lit.Parameters, lit.Rest = p.parseParameters()
 
 	if !p.expectPeek(token.LBRACE) {
 		return nil
@@ -410,6 +552,60 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 	return lit
 }
 
+// parseParameters parses the parameters of a function literal, each of which is a binding target,
+// followed by an optional rest parameter such as `...rest`.
+func (p *Parser) parseParameters() ([]ast.Expression, *ast.Ident) {
+	params := []ast.Expression{}
+	var rest *ast.Ident
+
+	if p.peekTokenIs(token.RPAREN) {
+		p.nextToken()
+		return params, nil
+	}
+
+	for {
+		p.nextToken()
+
+		if p.curTokenIs(token.ELLIPSIS) {
+			if !p.expectPeek(token.IDENT) {
+				return nil, nil
+			}
+			rest = &ast.Ident{Token: p.curToken, Value: p.curToken.Literal}
+			break
+		}
+
+		param := p.parseBindingTarget()
+		if param == nil {
+			return nil, nil
+		}
+		params = append(params, param)
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil, nil
+	}
+
+	return params, rest
+}
+
+// parseBindingTarget parses what a let statement or a function parameter binds, which is an
+// identifier, or an array or hash pattern to destructure the value with.
+func (p *Parser) parseBindingTarget() ast.Expression {
+	switch p.curToken.Type {
+	case token.IDENT, token.LBRACKET, token.LBRACE:
+		return p.parsePattern()
+	}
+
+	msg := fmt.Sprintf("expected identifier or pattern, got %s instead", p.curToken.Type)
+	p.errors = append(p.errors, msg)
+	return nil
+}
+
 func (p *Parser) parseFunctionParameters() []*ast.Ident {
 	idents := []*ast.Ident{}
 
@@ -452,12 +648,12 @@ func (p *Parser) parseExpressionList(end token.Type) []ast.Expression {
 	}
 
 	p.nextToken()
-	list = append(list, p.parseExpression(LOWEST))
+	list = append(list, p.parseElement())
 
 	for p.peekTokenIs(token.COMMA) {
 		p.nextToken()
 		p.nextToken()
-		list = append(list, p.parseExpression(LOWEST))
+		list = append(list, p.parseElement())
 	}
 
 	if !p.expectPeek(end) {
@@ -467,6 +663,19 @@ func (p *Parser) parseExpressionList(end token.Type) []ast.Expression {
 	return list
 }
 
+// parseElement parses an argument of a call or an element of an array literal, which may be an
+// array spread into the list, such as `...xs`.
+func (p *Parser) parseElement() ast.Expression {
+	if !p.curTokenIs(token.ELLIPSIS) {
+		return p.parseExpression(LOWEST)
+	}
+
+	spread := &ast.SpreadExpression{Token: p.curToken}
+	p.nextToken()
+	spread.Value = p.parseExpression(LOWEST)
+	return spread
+}
+
 func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
 	return &ast.CallExpression{
 		Token:     p.curToken,
@@ -490,13 +699,57 @@ func (p *Parser) parseArrayLiteral() ast.Expression {
 }
 
 func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
-	expr := &ast.IndexExpression{
+	tok := p.curToken
+
+	p.nextToken()
+	if p.curTokenIs(token.COLON) {
+		return p.parseSliceExpression(tok, left, nil)
+	}
+
+	index := p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(token.COLON) {
+		p.nextToken()
+		return p.parseSliceExpression(tok, left, index)
+	}
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+
+	return &ast.IndexExpression{
+		Token: tok,
+		Left:  left,
+		Index: index,
+	}
+}
+
+func (p *Parser) parseMemberExpression(left ast.Expression) ast.Expression {
+	expr := &ast.MemberExpression{
 		Token: p.curToken,
 		Left:  left,
 	}
 
-	p.nextToken()
-	expr.Index = p.parseExpression(LOWEST)
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	expr.Member = &ast.Ident{Token: p.curToken, Value: p.curToken.Literal}
+
+	return expr
+}
+
+// parseSliceExpression parses the rest of a slice expression after its colon.
+func (p *Parser) parseSliceExpression(tok token.Token, left, low ast.Expression) ast.Expression {
+	expr := &ast.SliceExpression{
+		Token: tok,
+		Left:  left,
+		Low:   low,
+	}
+
+	if !p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+		expr.High = p.parseExpression(LOWEST)
+	}
 
 	if !p.expectPeek(token.RBRACKET) {
 		return nil
@@ -508,11 +761,20 @@ func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 func (p *Parser) parseHashLiteral() ast.Expression {
 	hash := &ast.HashLiteral{
 		Token: p.curToken,
-		Pairs: make(map[ast.Expression]ast.Expression),
+		Pairs: []ast.HashPair{},
 	}
 
 	for !p.peekTokenIs(token.RBRACE) {
 		p.nextToken()
+
+		if p.curTokenIs(token.ELLIPSIS) {
+			hash.Pairs = append(hash.Pairs, ast.HashPair{Key: p.parseElement()})
+			if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+				return nil
+			}
+			continue
+		}
+
 		key := p.parseExpression(LOWEST)
 
 		if !p.expectPeek(token.COLON) {
@@ -521,7 +783,7 @@ func (p *Parser) parseHashLiteral() ast.Expression {
 
 		p.nextToken()
 		value := p.parseExpression(LOWEST)
-		hash.Pairs[key] = value
+		hash.Pairs = append(hash.Pairs, ast.HashPair{Key: key, Value: value})
 
 		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
 			return nil
@@ -535,6 +797,181 @@ func (p *Parser) parseHashLiteral() ast.Expression {
 	return hash
 }
 
+func (p *Parser) parseMatchExpression() ast.Expression {
+	expr := &ast.MatchExpression{
+		Token: p.curToken,
+		Arms:  []ast.MatchArm{},
+	}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	p.nextToken()
+	expr.Value = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) || !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+
+		var arm ast.MatchArm
+		if arm.Pattern = p.parsePattern(); arm.Pattern == nil {
+			return nil
+		}
+
+		if p.peekTokenIs(token.IF) {
+			p.nextToken()
+			p.nextToken()
+			arm.Guard = p.parseExpression(LOWEST)
+		}
+
+		if !p.expectPeek(token.ARROW) {
+			return nil
+		}
+
+		p.nextToken()
+		arm.Body = p.parseExpression(LOWEST)
+		expr.Arms = append(expr.Arms, arm)
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	if len(expr.Arms) == 0 {
+		p.errors = append(p.errors, "match expression needs at least one arm")
+		return nil
+	}
+
+	return expr
+}
+
+// parsePattern parses a pattern, which is a literal, an identifier to bind (`_` binds nothing),
+// an array pattern or a hash pattern.
+func (p *Parser) parsePattern() ast.Expression {
+	switch p.curToken.Type {
+	case token.IDENT:
+		return p.parseIdent()
+	case token.INT:
+		return p.parseIntegerLiteral()
+	case token.FLOAT:
+		return p.parseFloatLiteral()
+	case token.STRING:
+		return p.parseStringLiteral()
+	case token.TRUE, token.FALSE:
+		return p.parseBoolean()
+	case token.MINUS:
+		if p.peekTokenIs(token.INT) || p.peekTokenIs(token.FLOAT) {
+			return p.parsePrefixExpression()
+		}
+	case token.LBRACKET:
+		return p.parseArrayPattern()
+	case token.LBRACE:
+		return p.parseHashPattern()
+	}
+
+	msg := fmt.Sprintf("expected pattern, got %s instead", p.curToken.Type)
+	p.errors = append(p.errors, msg)
+	return nil
+}
+
+func (p *Parser) parseArrayPattern() ast.Expression {
+	pattern := &ast.ArrayPattern{
+		Token:    p.curToken,
+		Elements: []ast.Expression{},
+	}
+
+	for !p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+
+		if p.curTokenIs(token.DOTDOT) {
+			if !p.expectPeek(token.IDENT) {
+				return nil
+			}
+			pattern.Rest = &ast.Ident{Token: p.curToken, Value: p.curToken.Literal}
+			break
+		}
+
+		elem := p.parsePattern()
+		if elem == nil {
+			return nil
+		}
+		pattern.Elements = append(pattern.Elements, elem)
+
+		if !p.peekTokenIs(token.RBRACKET) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+
+	return pattern
+}
+
+// parseHashPattern parses a hash pattern. Keys must be literals, except that an identifier key
+// stands for the string of its name: `{name: n}` is short for `{"name": n}`, and `{name}` alone is
+// short for `{"name": name}`.
+func (p *Parser) parseHashPattern() ast.Expression {
+	pattern := &ast.HashPattern{
+		Token: p.curToken,
+		Pairs: []ast.HashPair{},
+	}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+
+		var pair ast.HashPair
+		switch p.curToken.Type {
+		case token.IDENT:
+			pair.Key = &ast.StringLiteral{
+				Token: token.Token{Type: token.STRING, Literal: p.curToken.Literal},
+				Value: p.curToken.Literal,
+			}
+			if !p.peekTokenIs(token.COLON) {
+				pair.Value = p.parseIdent()
+			}
+		case token.STRING, token.INT, token.FLOAT, token.TRUE, token.FALSE, token.MINUS:
+			if pair.Key = p.parsePattern(); pair.Key == nil {
+				return nil
+			}
+		default:
+			msg := fmt.Sprintf("expected hash pattern key, got %s instead", p.curToken.Type)
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+
+		if pair.Value == nil {
+			if !p.expectPeek(token.COLON) {
+				return nil
+			}
+			p.nextToken()
+			if pair.Value = p.parsePattern(); pair.Value == nil {
+				return nil
+			}
+		}
+		pattern.Pairs = append(pattern.Pairs, pair)
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	return pattern
+}
+
 func (p *Parser) parseMacroLiteral() ast.Expression {
 	tok := p.curToken
 
@@ -54,6 +54,37 @@ func checkParserErrors(t *testing.T, p *Parser) {
 	t.FailNow()
 }
 
+func TestConstStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"const x = 5;", "const x = 5;"},
+		{"const [a, ..rest] = xs;", "const [a, ..rest] = xs;"},
+		{"const {name} = person;", "const {name: name} = person;"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if l := len(program.Statements); l != 1 {
+			t.Fatalf("program.Statements does not contain 1 statement. got=%d", l)
+		}
+		stmt, ok := program.Statements[0].(*ast.LetStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not *ast.LetStatement. got=%T", program.Statements[0])
+		}
+		if !stmt.IsConst() {
+			t.Errorf("%s: stmt.IsConst() is false", tt.input)
+		}
+		if got := stmt.String(); got != tt.expected {
+			t.Errorf("expected %q, but got %q", tt.expected, got)
+		}
+	}
+}
+
 func TestLetStatementErrors(t *testing.T) {
 	tests := []struct {
 		input string
@@ -61,6 +92,8 @@ func TestLetStatementErrors(t *testing.T) {
 		{"let = 5;"},
 		{"let x = ;"},
 		{"let x 1;"},
+		{"const = 5;"},
+		{"const x;"},
 	}
 
 	for _, tt := range tests {
@@ -87,12 +120,76 @@ func testLetStatement(t *testing.T, s ast.Statement, name string) {
 		t.Errorf("s not *ast.LetStatement. got=%T", s)
 	}
 
-	if letStmt.Name.Value != name {
-		t.Errorf("letStmt.Name.Value not '%s'. got=%s", name, letStmt.Name.Value)
+	ident, ok := letStmt.Name.(*ast.Ident)
+	if !ok {
+		t.Fatalf("letStmt.Name not *ast.Ident. got=%T", letStmt.Name)
+	}
+
+	if ident.Value != name {
+		t.Errorf("letStmt.Name.Value not '%s'. got=%s", name, ident.Value)
 	}
 
-	if letStmt.Name.TokenLiteral() != name {
-		t.Errorf("s.Name not '%s'. got=%s", name, letStmt.Name)
+	if ident.TokenLiteral() != name {
+		t.Errorf("s.Name not '%s'. got=%s", name, ident)
+	}
+}
+
+func TestLetStatementsWithPatterns(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let [a, b, ..rest] = arr;", "let [a, b, ..rest] = arr;"},
+		{"let [] = arr;", "let [] = arr;"},
+		{"let {name, age: years} = person;", "let {name: name, age: years} = person;"},
+		{`let {"first name": first, pos: [x, _]} = h;`, "let {first name: first, pos: [x, _]} = h;"},
+		{"let [[a], {b}] = c;", "let [[a], {b: b}] = c;"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if l := len(program.Statements); l != 1 {
+			t.Fatalf("program.Statements does not contain %d statements. got=%d", 1, l)
+		}
+		if _, ok := program.Statements[0].(*ast.LetStatement); !ok {
+			t.Fatalf("stmt not *ast.LetStatement. got=%T", program.Statements[0])
+		}
+		if got := program.String(); got != tt.expected {
+			t.Errorf("program.String() wrong. want=%q, got=%q", tt.expected, got)
+		}
+	}
+}
+
+func TestBindingTargetErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let 1 = 2;", "expected identifier or pattern, got INT instead"},
+		{`let "a" = 2;`, "expected identifier or pattern, got STRING instead"},
+		{"let [a, = 2;", "expected pattern, got = instead"},
+		{"let [a] 2;", "expected next token to be =, got INT instead"},
+		{"fn(1) {}", "expected identifier or pattern, got INT instead"},
+		{"fn(a, ) {}", "expected identifier or pattern, got ) instead"},
+		{"fn(a b) {}", "expected next token to be ), got IDENT instead"},
+		{"fn([a, ..b) {}", "expected next token to be ], got ) instead"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 {
+			t.Errorf("%s: expected parser errors, but got none", tt.input)
+			continue
+		}
+		if errors[0] != tt.expected {
+			t.Errorf("%s: expected error %q, but got %q", tt.input, tt.expected, errors[0])
+		}
 	}
 }
 
@@ -132,6 +229,35 @@ func TestReturnStatement(t *testing.T) {
 	}
 }
 
+func TestThrowStatement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`throw "boom";`, "throw boom;"},
+		{"throw x", "throw x;"},
+		{`throw {"message": m};`, "throw {message: m};"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if l := len(program.Statements); l != 1 {
+			t.Fatalf("program.Statements does not contain %d statements. got=%d", 1, l)
+		}
+
+		throwStmt, ok := program.Statements[0].(*ast.ThrowStatement)
+		if !ok {
+			t.Fatalf("stmt not *ast.ThrowStatement. got=%T", program.Statements[0])
+		}
+		if got := throwStmt.String(); got != tt.expected {
+			t.Errorf("throwStmt.String() wrong. want=%q, got=%q", tt.expected, got)
+		}
+	}
+}
+
 func TestIdentifierExpression(t *testing.T) {
 	input := "foobar"
 
@@ -332,6 +458,9 @@ func TestParsingInfixExpressions(t *testing.T) {
 		{"5 / 5;", 5, "/", 5},
 		{"5 > 5;", 5, ">", 5},
 		{"5 < 5;", 5, "<", 5},
+		{"5 >= 5;", 5, ">=", 5},
+		{"5 <= 5;", 5, "<=", 5},
+		{"a in b;", "a", "in", "b"},
 		{"5 == 5;", 5, "==", 5},
 		{"5 != 5;", 5, "!=", 5},
 		{"true == true", true, "==", true},
@@ -397,6 +526,9 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 		{"3 + 4; -5 * 5", "(3 + 4)((-5) * 5)"},
 		{"5 > 4 == 3 < 4", "((5 > 4) == (3 < 4))"},
 		{"5 < 4 != 3 > 4", "((5 < 4) != (3 > 4))"},
+		{"5 <= 4 == 3 >= 4", "((5 <= 4) == (3 >= 4))"},
+		{"a + b <= c * d", "((a + b) <= (c * d))"},
+		{"a + b in c == !d in e", "(((a + b) in c) == ((!d) in e))"},
 		{"3 + 4 * 5 == 3 * 1 + 4 * 5", "((3 + (4 * 5)) == ((3 * 1) + (4 * 5)))"},
 		{"true", "true"},
 		{"false", "false"},
@@ -415,6 +547,34 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 		{"add(a + b + c * d / f + g)", "add((((a + b) + ((c * d) / f)) + g))"},
 		{"a * [1, 2, 3, 4][b * c] * d", "((a * ([1, 2, 3, 4][(b * c)])) * d)"},
 		{"add(a * b[2], b[1], 2 * [1, 2][1])", "add((a * (b[2])), (b[1]), (2 * ([1, 2][1])))"},
+		{"a * b[1 + c:-d][0]", "(a * ((b[(1 + c):(-d)])[0]))"},
+		{"s[:n] + s[n:]", "((s[:n]) + (s[n:]))"},
+		{"a.b.c", "((a.b).c)"},
+		{"-a.b * c.d", "((-(a.b)) * (c.d))"},
+		{"a.b[0].c", "(((a.b)[0]).c)"},
+		{"a.f(1, b.c)", "(a.f)(1, (b.c))"},
+		{"a.f().g()", "((a.f)().g)()"},
+		{"5.abs() + 1.5", "((5.abs)() + 1.5)"},
+		{"a ? b : c", "(a ? b : c)"},
+		{"a == b ? x + 1 : y * 2", "((a == b) ? (x + 1) : (y * 2))"},
+		{"a ? b : c ? d : e", "(a ? b : (c ? d : e))"},
+		{"a ? b ? c : d : e", "(a ? (b ? c : d) : e)"},
+		{"-a ? f(b) : c[0]", "((-a) ? f(b) : (c[0]))"},
+		{"f(a ? b : c, d)", "f((a ? b : c), d)"},
+		{"x in xs ? 1 : 0", "((x in xs) ? 1 : 0)"},
+		{"(a ? b : c) + 1", "((a ? b : c) + 1)"},
+		{"a[b ? 1 : 2]", "(a[(b ? 1 : 2)])"},
+		{"xs |> f", "f(xs)"},
+		{"xs |> map(f) |> filter(g) |> sum", "sum(filter(map(xs, f), g))"},
+		{"a + b |> f(c * d)", "f((a + b), (c * d))"},
+		{"xs |> len == 3", "(len(xs) == 3)"},
+		{"x in xs |> keys", "(x in keys(xs))"},
+		{"-x |> abs", "abs((-x))"},
+		{"x |> m.f(y)", "(m.f)(x, y)"},
+		{"x |> fs[0]", "(fs[0])(x)"},
+		{"x |> fn(y) { y }", "fn(y) y(x)"},
+		{"c ? x |> f : y |> g", "(c ? f(x) : g(y))"},
+		{"h(x |> f, y)", "h(f(x), y)"},
 	}
 
 	for _, tt := range tests {
@@ -560,6 +720,304 @@ func TestIfElseExpression(t *testing.T) {
 	testIdent(t, alt.Expression, "y")
 }
 
+func TestElseIfExpression(t *testing.T) {
+	input := "if (x < y) { x } else if (x > y) { y } else if (x == 0) { 0 } else { z }"
+
+	p := New(lexer.New(input))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if l := len(program.Statements); l != 1 {
+		t.Fatalf("program.Statements does not contain %d statements. got=%d", 1, l)
+	}
+
+	want := "if(x < y) xelse if(x > y) yelse if(x == 0) 0else z"
+	if got := program.String(); got != want {
+		t.Errorf("program.String() wrong. want=%q, got=%q", want, got)
+	}
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	expr := stmt.Expression.(*ast.IfExpression)
+	for _, cond := range []string{"(x < y)", "(x > y)", "(x == 0)"} {
+		if got := expr.Condition.String(); got != cond {
+			t.Fatalf("condition wrong. want=%q, got=%q", cond, got)
+		}
+		if expr.Alternative == nil || len(expr.Alternative.Statements) != 1 {
+			t.Fatalf("expr.Alternative does not contain 1 statement. got=%+v", expr.Alternative)
+		}
+		alt, ok := expr.Alternative.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("Statements[0] is not *ast.ExpressionStatement. got=%T", expr.Alternative.Statements[0])
+		}
+		if next, ok := alt.Expression.(*ast.IfExpression); ok {
+			expr = next
+			continue
+		}
+		testIdent(t, alt.Expression, "z")
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"if (a) { 1 } else if { 2 }", "expected next token to be (, got { instead"},
+		{"if (a) { 1 } else if (b) 2", "expected next token to be {, got INT instead"},
+		{"if (a) { 1 } else 2", "expected next token to be {, got INT instead"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 {
+			t.Errorf("%s: expected parser errors, but got none", tt.input)
+			continue
+		}
+		if errors[0] != tt.expected {
+			t.Errorf("%s: expected error %q, but got %q", tt.input, tt.expected, errors[0])
+		}
+	}
+}
+
+func TestConditionalExpression(t *testing.T) {
+	p := New(lexer.New("x > 0 ? x : -x"))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	expr, ok := stmt.Expression.(*ast.ConditionalExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not *ast.ConditionalExpression. got=%T", stmt.Expression)
+	}
+	testInfixExpression(t, expr.Condition, "x", ">", 0)
+	testIdent(t, expr.Consequence, "x")
+	if got := expr.Alternative.String(); got != "(-x)" {
+		t.Errorf("expr.Alternative wrong. want=%q, got=%q", "(-x)", got)
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a ? b", "expected next token to be :, got EOF instead"},
+		{"a ? b c", "expected next token to be :, got IDENT instead"},
+		{"a ? : c", "no prefix parse function for : found"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 {
+			t.Errorf("%s: expected parser errors, but got none", tt.input)
+			continue
+		}
+		if errors[0] != tt.expected {
+			t.Errorf("%s: expected error %q, but got %q", tt.input, tt.expected, errors[0])
+		}
+	}
+}
+
+func TestPipeExpression(t *testing.T) {
+	p := New(lexer.New("xs |> push(1)"))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	call, ok := stmt.Expression.(*ast.CallExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not *ast.CallExpression. got=%T", stmt.Expression)
+	}
+	testIdent(t, call.Function, "push")
+	if l := len(call.Arguments); l != 2 {
+		t.Fatalf("wrong length of arguments. want=2, got=%d", l)
+	}
+	testIdent(t, call.Arguments[0], "xs")
+	testIntegerLiteral(t, call.Arguments[1], 1)
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"xs |>", "no prefix parse function for EOF found"},
+		{"xs |> |> f", "no prefix parse function for |> found"},
+		{"|> f", "no prefix parse function for |> found"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 {
+			t.Errorf("%s: expected parser errors, but got none", tt.input)
+			continue
+		}
+		if errors[0] != tt.expected {
+			t.Errorf("%s: expected error %q, but got %q", tt.input, tt.expected, errors[0])
+		}
+	}
+}
+
+func TestTryExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"try { f() } catch (e) { e }", "try f() catch (e) e"},
+		{"try { f() } catch { 1 }", "try f() catch 1"},
+		{"try { f() } finally { g() }", "try f() finally g()"},
+		{"try { f() } catch (e) { 1 } finally { g() }", "try f() catch (e) 1 finally g()"},
+		{"let x = try { f() } catch (e) { 0 };", "let x = try f() catch (e) 0;"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if l := len(program.Statements); l != 1 {
+			t.Fatalf("program.Statements does not contain %d statements. got=%d", 1, l)
+		}
+		if got := program.String(); got != tt.expected {
+			t.Errorf("program.String() wrong. want=%q, got=%q", tt.expected, got)
+		}
+	}
+
+	p := New(lexer.New("try { f() } catch (err) { g(err) }"))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	tryExpr, ok := stmt.Expression.(*ast.TryExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not *ast.TryExpression. got=%T", stmt.Expression)
+	}
+	if l := len(tryExpr.Block.Statements); l != 1 {
+		t.Errorf("try block does not contain 1 statement. got=%d", l)
+	}
+	testIdent(t, tryExpr.CatchParam, "err")
+	if tryExpr.Finally != nil {
+		t.Errorf("tryExpr.Finally was not nil. got=%+v", tryExpr.Finally)
+	}
+}
+
+func TestTryExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"try { f() }", "expected catch or finally after try block"},
+		{"try f()", "expected next token to be {, got IDENT instead"},
+		{"try { f() } catch (1) {}", "expected next token to be IDENT, got INT instead"},
+		{"try { f() } catch (e {}", "expected next token to be ), got { instead"},
+		{"try { f() } finally", "expected next token to be {, got EOF instead"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 {
+			t.Errorf("%s: expected parser errors, but got none", tt.input)
+			continue
+		}
+		if errors[0] != tt.expected {
+			t.Errorf("%s: expected error %q, but got %q", tt.input, tt.expected, errors[0])
+		}
+	}
+}
+
+func TestMatchExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"match (x) { 1 => a }", "match (x) {1 => a}"},
+		{`match (x) { 1 => "one", -2.5 => f(x), true => 0, "s" => 1, _ => 2, }`,
+			"match (x) {1 => one, (-2.5) => f(x), true => 0, s => 1, _ => 2}"},
+		{"match (f(x)) { n if n > 0 => n + 1, n => -n }", "match (f(x)) {n if (n > 0) => (n + 1), n => (-n)}"},
+		{"match (xs) { [] => 0, [a, [b]] => a, [h, ..t] => t, [.._] => 1 }",
+			"match (xs) {[] => 0, [a, [b]] => a, [h, ..t] => t, [.._] => 1}"},
+		{`match (h) { {} => 0, {"k": [v], 1: _} => v, {name, age} => name }`,
+			"match (h) {{} => 0, {k: [v], 1: _} => v, {name: name, age: age} => name}"},
+		{"match (h) { {age: years, pos: [x, y]} => x }", "match (h) {{age: years, pos: [x, y]} => x}"},
+		{"let y = match (x) { _ => 1 };", "let y = match (x) {_ => 1};"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if l := len(program.Statements); l != 1 {
+			t.Fatalf("program.Statements does not contain %d statements. got=%d", 1, l)
+		}
+		if got := program.String(); got != tt.expected {
+			t.Errorf("program.String() wrong. want=%q, got=%q", tt.expected, got)
+		}
+	}
+
+	p := New(lexer.New("match (x) { [h, ..t] if h => t }"))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	matchExpr, ok := stmt.Expression.(*ast.MatchExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not *ast.MatchExpression. got=%T", stmt.Expression)
+	}
+	testIdent(t, matchExpr.Value, "x")
+	if l := len(matchExpr.Arms); l != 1 {
+		t.Fatalf("matchExpr.Arms does not contain 1 arm. got=%d", l)
+	}
+	arm := matchExpr.Arms[0]
+	pattern, ok := arm.Pattern.(*ast.ArrayPattern)
+	if !ok {
+		t.Fatalf("arm.Pattern is not *ast.ArrayPattern. got=%T", arm.Pattern)
+	}
+	testIdent(t, pattern.Elements[0], "h")
+	testIdent(t, pattern.Rest, "t")
+	testIdent(t, arm.Guard, "h")
+	testIdent(t, arm.Body, "t")
+}
+
+func TestMatchExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"match (x) {}", "match expression needs at least one arm"},
+		{"match x { 1 => 2 }", "expected next token to be (, got IDENT instead"},
+		{"match (x) { 1 2 }", "expected next token to be =>, got INT instead"},
+		{"match (x) { 1 => 2 3 => 4 }", "expected next token to be ,, got INT instead"},
+		{"match (x) { f(y) => 1 }", "expected next token to be =>, got ( instead"},
+		{"match (x) { (1) => 1 }", "expected pattern, got ( instead"},
+		{"match (x) { [a, ..] => 1 }", "expected next token to be IDENT, got ] instead"},
+		{"match (x) { [..a, b] => 1 }", "expected next token to be ], got , instead"},
+		{"match (x) { {k: } => 1 }", "expected pattern, got } instead"},
+		{`match (x) { {"k"} => 1 }`, "expected next token to be :, got } instead"},
+		{"match (x) { {[1]: v} => 1 }", "expected hash pattern key, got [ instead"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 {
+			t.Errorf("%s: expected parser errors, but got none", tt.input)
+			continue
+		}
+		if errors[0] != tt.expected {
+			t.Errorf("%s: expected error %q, but got %q", tt.input, tt.expected, errors[0])
+		}
+	}
+}
+
 func TestFunctionLiteralParsing(t *testing.T) {
 	input := "fn(x, y) { x + y; }"
 
@@ -611,6 +1069,8 @@ func TestFunctionParameterParsing(t *testing.T) {
 		{"fn() {}", []string{}},
 		{"fn(x) {};", []string{"x"}},
 		{"fn(x, y, z) {};", []string{"x", "y", "z"}},
+		{"fn([x, ..y], {z}) {};", []string{"[x, ..y]", "{z: z}"}},
+		{"fn(x, ...y) {};", []string{"x"}},
 	}
 
 	for _, tt := range tests {
@@ -625,8 +1085,105 @@ func TestFunctionParameterParsing(t *testing.T) {
 			t.Errorf("length parameters wrong. want=%d, got=%d", len(tt.expected), len(f.Parameters))
 		}
 
-		for i, ident := range tt.expected {
-			testLiteralExpression(t, f.Parameters[i], ident)
+		for i, param := range tt.expected {
+			if got := f.Parameters[i].String(); got != param {
+				t.Errorf("parameter %d wrong. want=%q, got=%q", i, param, got)
+			}
+		}
+	}
+}
+
+func TestRestParameterParsing(t *testing.T) {
+	tests := []struct {
+		input        string
+		expectedRest string
+		expected     string
+	}{
+		{"fn() {}", "", "fn() "},
+		{"fn(...args) {}", "args", "fn(...args) "},
+		{"fn(a, [b], ...args) { args }", "args", "fn(a, [b], ...args) args"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		f := stmt.Expression.(*ast.FunctionLiteral)
+
+		if tt.expectedRest == "" {
+			if f.Rest != nil {
+				t.Errorf("f.Rest was not nil. got=%+v", f.Rest)
+			}
+		} else {
+			testIdent(t, f.Rest, tt.expectedRest)
+		}
+
+		if got := f.String(); got != tt.expected {
+			t.Errorf("f.String() wrong. want=%q, got=%q", tt.expected, got)
+		}
+	}
+}
+
+func TestParsingSpreadExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"f(...xs)", "f(...xs)"},
+		{"f(1, ...xs, ...g(y), 2)", "f(1, ...xs, ...g(y), 2)"},
+		{"[...a, 1, ...b + c]", "[...a, 1, ...(b + c)]"},
+		{`{...h, "k": 1, ...g()}`, "{...h, k: 1, ...g()}"},
+		{"{...h,}", "{...h}"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if got := program.String(); got != tt.expected {
+			t.Errorf("program.String() wrong. want=%q, got=%q", tt.expected, got)
+		}
+	}
+
+	p := New(lexer.New("f(...xs)"))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	call := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.CallExpression)
+	spread, ok := call.Arguments[0].(*ast.SpreadExpression)
+	if !ok {
+		t.Fatalf("call.Arguments[0] is not *ast.SpreadExpression. got=%T", call.Arguments[0])
+	}
+	testIdent(t, spread.Value, "xs")
+}
+
+func TestSpreadErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"fn(...) {}", "expected next token to be IDENT, got ) instead"},
+		{"fn(...a, b) {}", "expected next token to be ), got , instead"},
+		{"fn(...[a]) {}", "expected next token to be IDENT, got [ instead"},
+		{"let ...a = b;", "expected identifier or pattern, got ... instead"},
+		{"...xs", "no prefix parse function for ... found"},
+		{"{...h: 1}", "expected next token to be ,, got : instead"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 {
+			t.Errorf("%s: expected parser errors, but got none", tt.input)
+			continue
+		}
+		if errors[0] != tt.expected {
+			t.Errorf("%s: expected error %q, but got %q", tt.input, tt.expected, errors[0])
 		}
 	}
 }
@@ -750,6 +1307,58 @@ func TestParsingIndexExpressions(t *testing.T) {
 	testInfixExpression(t, idxExpr.Index, 1, "+", 1)
 }
 
+func TestParsingSliceExpressions(t *testing.T) {
+	tests := []struct {
+		input string
+		low   interface{}
+		high  interface{}
+	}{
+		{"myArray[1:2]", 1, 2},
+		{"myArray[1:]", 1, nil},
+		{"myArray[:b]", nil, "b"},
+		{"myArray[:]", nil, nil},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if l := len(program.Statements); l != 1 {
+			t.Fatalf("program has not %d statement. got=%d", 1, l)
+		}
+
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not *ast.ExpressionStatement. got=%T",
+				program.Statements[0])
+		}
+
+		sliceExpr, ok := stmt.Expression.(*ast.SliceExpression)
+		if !ok {
+			t.Fatalf("sliceExpr not *ast.SliceExpression. got=%T", stmt.Expression)
+		}
+
+		testIdent(t, sliceExpr.Left, "myArray")
+
+		if tt.low == nil {
+			if sliceExpr.Low != nil {
+				t.Errorf("sliceExpr.Low is not nil. got=%s", sliceExpr.Low)
+			}
+		} else {
+			testLiteralExpression(t, sliceExpr.Low, tt.low)
+		}
+
+		if tt.high == nil {
+			if sliceExpr.High != nil {
+				t.Errorf("sliceExpr.High is not nil. got=%s", sliceExpr.High)
+			}
+		} else {
+			testLiteralExpression(t, sliceExpr.High, tt.high)
+		}
+	}
+}
+
 func TestParsingHashLiterals(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -818,8 +1427,9 @@ func TestParsingHashLiterals(t *testing.T) {
 			t.Fatalf("hash not *ast.HashLiteral. got=%T", stmt.Expression)
 		}
 
-		for key, value := range hash.Pairs {
-			switch key := key.(type) {
+		for _, pair := range hash.Pairs {
+			value := pair.Value
+			switch key := pair.Key.(type) {
 			case *ast.StringLiteral:
 				switch expected := tt.expected.(type) {
 				case map[string]int64:
@@ -860,6 +1470,81 @@ func TestParsingHashLiterals(t *testing.T) {
 	}
 }
 
+func TestParsingMemberExpressions(t *testing.T) {
+	input := "config.db"
+
+	p := New(lexer.New(input))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not *ast.ExpressionStatement. got=%T", program.Statements[0])
+	}
+
+	memberExpr, ok := stmt.Expression.(*ast.MemberExpression)
+	if !ok {
+		t.Fatalf("exp not *ast.MemberExpression. got=%T", stmt.Expression)
+	}
+
+	testIdent(t, memberExpr.Left, "config")
+	testIdent(t, memberExpr.Member, "db")
+}
+
+func TestParsingMemberExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a.1", "expected next token to be IDENT, got INT instead"},
+		{"a.", "expected next token to be IDENT, got EOF instead"},
+		{`a."b"`, "expected next token to be IDENT, got STRING instead"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 {
+			t.Errorf("%s: expected parser errors, but got none", tt.input)
+			continue
+		}
+		if errors[0] != tt.expected {
+			t.Errorf("%s: expected error %q, but got %q", tt.input, tt.expected, errors[0])
+		}
+	}
+}
+
+func TestParsingHashLiteralsKeepSourceOrder(t *testing.T) {
+	input := `{"b": 1, "a": 2, 3: 3, true: 4, "c": 5}`
+
+	p := New(lexer.New(input))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	hash, ok := stmt.Expression.(*ast.HashLiteral)
+	if !ok {
+		t.Fatalf("hash not *ast.HashLiteral. got=%T", stmt.Expression)
+	}
+
+	wantKeys := []string{"b", "a", "3", "true", "c"}
+	if l := len(hash.Pairs); l != len(wantKeys) {
+		t.Fatalf("hash.Pairs has wrong length. want=%d, got=%d", len(wantKeys), l)
+	}
+	for i, pair := range hash.Pairs {
+		if got := pair.Key.String(); got != wantKeys[i] {
+			t.Errorf("hash.Pairs[%d] has wrong key. want=%q, got=%q", i, wantKeys[i], got)
+		}
+		testIntegerLiteral(t, pair.Value, int64(i+1))
+	}
+
+	if got, want := hash.String(), "{b: 1, a: 2, 3: 3, true: 4, c: 5}"; got != want {
+		t.Errorf("hash.String() wrong. want=%q, got=%q", want, got)
+	}
+}
+
 func TestMacroLiteralParsing(t *testing.T) {
 	input := `macro(x, y) { x + y; }`
 
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"os"
 
 	"github.com/skatsuta/monkey-interpreter/eval"
 	"github.com/skatsuta/monkey-interpreter/lexer"
@@ -13,14 +14,17 @@ import (
 
 const prompt = ">> "
 
-// Start starts Monkey REPL.
-func Start(in io.Reader, out io.Writer) {
+// Start starts Monkey REPL. The environment of the session is further configured by `options`.
+func Start(in io.Reader, out io.Writer, options ...object.EnvironmentOption) {
 	scanner := bufio.NewScanner(in)
-	env := object.NewEnvironment()
+	// Modules are imported relative to the current directory.
+	importer := eval.NewImporter(eval.NewFSLoader(os.DirFS(".")))
+	options = append([]object.EnvironmentOption{object.WithOutput(out), object.WithImporter(importer)}, options...)
+	env := object.NewEnvironment(options...)
 	macroEnv := object.NewEnvironment()
 
 	for {
-		fmt.Print(prompt)
+		fmt.Fprint(out, prompt)
 		if !scanner.Scan() {
 			return
 		}
@@ -37,7 +41,11 @@ func Start(in io.Reader, out io.Writer) {
 
 		// Process macros
 		eval.DefineMacros(program, macroEnv)
-		expanded := eval.ExpandMacros(program, macroEnv)
+		expanded, err := eval.ExpandMacros(program, macroEnv)
+		if err != nil {
+			printParserErrors(out, []string{err.Error()})
+			continue
+		}
 
 		// Evaluate AST
 		evaluated := eval.Eval(expanded, env)
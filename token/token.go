@@ -34,10 +34,16 @@ const (
 	LT = "<"
 	// GT is a token ype for 'greater than' operator.
 	GT = ">"
+	// LTE is a token type for 'less than or equal to' operator.
+	LTE = "<="
+	// GTE is a token type for 'greater than or equal to' operator.
+	GTE = ">="
 	// EQ is a token type for equality operator.
 	EQ = "=="
 	// NEQ is a token type for not equality operator.
 	NEQ = "!="
+	// PIPE is a token type for pipeline operator.
+	PIPE = "|>"
 
 	// COMMA is a token type for commas.
 	COMMA = ","
@@ -45,6 +51,16 @@ const (
 	SEMICOLON = ";"
 	// COLON is a token type for colons.
 	COLON = ":"
+	// QUESTION is a token type for question marks of conditional expressions.
+	QUESTION = "?"
+	// DOT is a token type for member access operators.
+	DOT = "."
+	// DOTDOT is a token type for rest elements in array patterns.
+	DOTDOT = ".."
+	// ELLIPSIS is a token type for rest parameters and spread elements.
+	ELLIPSIS = "..."
+	// ARROW is a token type for arrows separating patterns from expressions in match arms.
+	ARROW = "=>"
 
 	// LPAREN is a token type for left parentheses.
 	LPAREN = "("
@@ -63,6 +79,8 @@ const (
 	FUNCTION = "FUNCTION"
 	// LET is a token type for lets.
 	LET = "LET"
+	// CONST is a token type for consts.
+	CONST = "CONST"
 	// TRUE is a token type for true.
 	TRUE = "TRUE"
 	// FALSE is a token type for false.
@@ -75,6 +93,18 @@ const (
 	RETURN = "RETURN"
 	// MACRO is a token type for macros.
 	MACRO = "MACRO"
+	// IN is a token type for membership operator.
+	IN = "IN"
+	// THROW is a token type for throw.
+	THROW = "THROW"
+	// TRY is a token type for try.
+	TRY = "TRY"
+	// CATCH is a token type for catch.
+	CATCH = "CATCH"
+	// FINALLY is a token type for finally.
+	FINALLY = "FINALLY"
+	// MATCH is a token type for match.
+	MATCH = "MATCH"
 )
 
 // Token represents a token which has a token type and literal.
@@ -85,14 +115,21 @@ type Token struct {
 
 // Language keywords
 var keywords = map[string]Type{
-	"fn":     FUNCTION,
-	"let":    LET,
-	"true":   TRUE,
-	"false":  FALSE,
-	"if":     IF,
-	"else":   ELSE,
-	"return": RETURN,
-	"macro":  MACRO,
+	"fn":      FUNCTION,
+	"let":     LET,
+	"const":   CONST,
+	"true":    TRUE,
+	"false":   FALSE,
+	"if":      IF,
+	"else":    ELSE,
+	"return":  RETURN,
+	"macro":   MACRO,
+	"in":      IN,
+	"throw":   THROW,
+	"try":     TRY,
+	"catch":   CATCH,
+	"finally": FINALLY,
+	"match":   MATCH,
 }
 
 // LookupIdent checks the language keywords to see whether the given identifier is a keyword.